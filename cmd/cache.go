@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/docaura/docaura-cli/pkg/docgen"
+	"github.com/spf13/cobra"
+	"time"
+)
+
+var (
+	// Cache command flags
+	cacheProjectDir string
+	cacheOlderThan  time.Duration
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "LLM response cache management commands",
+	Long: `Commands for inspecting and maintaining the on-disk cache of LLM responses.
+Cached responses are reused for symbols whose signature and documentation
+have not changed since the previous run.`,
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show cache statistics",
+	Long:  `Display the number of cached responses, their total size and age.`,
+	Args:  cobra.NoArgs,
+	RunE:  runCacheStats,
+	Example: `  # Show cache statistics for the current project
+  docaura cache stats`,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all cached responses",
+	Long: `Delete every cached LLM response for the project. Nothing is deleted if
+the cache directory contains files other than cached responses.`,
+	Args: cobra.NoArgs,
+	RunE: runCacheClear,
+	Example: `  # Clear the cache for the current project
+  docaura cache clear`,
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove stale cached responses",
+	Long:  `Delete cached LLM responses that have not been used within the given duration.`,
+	Args:  cobra.NoArgs,
+	RunE:  runCachePrune,
+	Example: `  # Remove entries unused for 30 days (default)
+  docaura cache prune
+
+  # Remove entries unused for a week
+  docaura cache prune --older-than 168h`,
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheStatsCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+	cacheCmd.AddCommand(cachePruneCmd)
+
+	// Command-specific flags
+	cacheCmd.PersistentFlags().StringVarP(&cacheProjectDir, "dir", "d", ".", "project directory whose cache to manage")
+	cachePruneCmd.Flags().DurationVar(&cacheOlderThan, "older-than", 30*24*time.Hour, "remove entries unused for longer than this duration")
+}
+
+func runCacheStats(cmd *cobra.Command, args []string) error {
+	cache, err := loadCache()
+	if err != nil {
+		return err
+	}
+
+	stats, err := cache.Stats()
+	if err != nil {
+		return fmt.Errorf("read cache: %w", err)
+	}
+
+	fmt.Printf("Cache directory: %s\n", cache.Dir())
+	fmt.Printf("Entries:         %d\n", stats.Entries)
+	fmt.Printf("Size:            %d bytes\n", stats.SizeBytes)
+	if stats.Entries > 0 {
+		fmt.Printf("Oldest use:      %s\n", stats.Oldest.Format(time.RFC3339))
+		fmt.Printf("Newest use:      %s\n", stats.Newest.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	cache, err := loadCache()
+	if err != nil {
+		return err
+	}
+
+	if err := cache.Clear(); err != nil {
+		return fmt.Errorf("clear cache: %w", err)
+	}
+
+	fmt.Printf("✓ Cleared cache at %s\n", cache.Dir())
+	return nil
+}
+
+func runCachePrune(cmd *cobra.Command, args []string) error {
+	cache, err := loadCache()
+	if err != nil {
+		return err
+	}
+
+	removed, err := cache.Prune(cacheOlderThan)
+	if err != nil {
+		return fmt.Errorf("prune cache: %w", err)
+	}
+
+	fmt.Printf("✓ Removed %d cache entries unused for more than %v\n", removed, cacheOlderThan)
+	return nil
+}
+
+// loadCache resolves the project's cache directory from the configuration.
+func loadCache() (*docgen.Cache, error) {
+	config := GetGlobalConfig()
+	config.ProjectDir = cacheProjectDir
+
+	if err := config.LoadFromFile(config.ConfigFile); err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return docgen.NewCache(config.CacheDir), nil
+}
@@ -15,6 +15,29 @@ var (
 	style       string
 	examples    bool
 	private     bool
+	noAI        bool
+	noCache     bool
+	strict      bool
+	typeCheck   bool
+	graph       bool
+	mermaidURL  string
+	templateDir string
+	goos        string
+	goarch      string
+	buildTags   []string
+
+	// LLM provider flags
+	llmProvider    string
+	llmBaseURL     string
+	llmModel       string
+	llmAPIKeyEnv   string
+	llmTemperature float64
+
+	// LLM pipeline flags
+	concurrency       int
+	requestsPerMinute int
+	tokensPerMinute   int
+	maxRetries        int
 )
 
 var generateCmd = &cobra.Command{
@@ -41,7 +64,41 @@ for file changes to automatically regenerate documentation.`,
   docaura generate --style html
 
   # Include private symbols and disable examples
-  docaura generate --private --examples=false`,
+  docaura generate --private --examples=false
+
+  # Render source documentation only, without calling an LLM
+  docaura generate --no-ai
+
+  # Dry run against the built-in deterministic fake model
+  docaura generate --llm fake
+
+  # Override built-in templates with your own partials
+  docaura generate --templates ./doc-templates
+
+  # Fail the run if any AI enhancement fails
+  docaura generate --strict
+
+  # Resolve types, interfaces and constant values with the type checker
+  docaura generate --typecheck
+
+  # Embed the package dependency graph in the index page
+  docaura generate --graph
+
+  # Draw the graph of the HTML index with a pinned Mermaid release
+  docaura generate --style html --graph \
+    --mermaid-url https://cdn.jsdelivr.net/npm/mermaid@11.4.1/dist/mermaid.esm.min.mjs
+
+  # Document the Windows build of a package with extra build tags
+  docaura generate --goos windows --goarch amd64 --tags netgo,osusergo
+
+  # Stay within a provider's rate limits
+  docaura generate --concurrency 8 --rpm 30 --tpm 6000
+
+  # Use a local Ollama model
+  docaura generate --llm ollama --llm-model llama3
+
+  # Use Anthropic with a custom API key variable
+  docaura generate --llm anthropic --llm-api-key-env MY_ANTHROPIC_KEY`,
 }
 
 func init() {
@@ -55,6 +112,25 @@ func init() {
 	generateCmd.Flags().StringVarP(&style, "style", "s", "markdown", "documentation style (markdown, godoc, html)")
 	generateCmd.Flags().BoolVar(&examples, "examples", true, "generate AI-enhanced examples")
 	generateCmd.Flags().BoolVar(&private, "private", false, "include private (unexported) symbols")
+	generateCmd.Flags().BoolVar(&noAI, "no-ai", false, "disable all LLM calls and render source documentation only")
+	generateCmd.Flags().BoolVar(&noCache, "no-cache", false, "do not read or write the LLM response cache")
+	generateCmd.Flags().StringVar(&templateDir, "templates", "", "directory of template overrides (package.md.tmpl, func.md.tmpl, type.md.tmpl, ...)")
+	generateCmd.Flags().BoolVar(&strict, "strict", false, "exit with an error if any LLM enhancement fails")
+	generateCmd.Flags().BoolVar(&typeCheck, "typecheck", false, "type-check packages to resolve qualified types, interfaces and constant values (packages must build)")
+	generateCmd.Flags().BoolVar(&graph, "graph", false, "embed the package dependency graph in the index page")
+	generateCmd.Flags().StringVar(&mermaidURL, "mermaid-url", "", "URL of the Mermaid ES module the HTML index loads to draw the graph (default none: list imports as HTML)")
+	generateCmd.Flags().StringVar(&goos, "goos", "", "target operating system for build constraints (default host GOOS)")
+	generateCmd.Flags().StringVar(&goarch, "goarch", "", "target architecture for build constraints (default host GOARCH)")
+	generateCmd.Flags().StringSliceVar(&buildTags, "tags", nil, "comma-separated build tags to satisfy, as with go build -tags")
+	generateCmd.Flags().StringVar(&llmProvider, "llm", "", "LLM provider (groq, openai, ollama, anthropic, fake)")
+	generateCmd.Flags().StringVar(&llmBaseURL, "llm-base-url", "", "base URL of the LLM API endpoint")
+	generateCmd.Flags().StringVar(&llmModel, "llm-model", "", "LLM model name")
+	generateCmd.Flags().StringVar(&llmAPIKeyEnv, "llm-api-key-env", "", "environment variable holding the LLM API key")
+	generateCmd.Flags().Float64Var(&llmTemperature, "temperature", 0, "LLM sampling temperature (0 uses the provider default)")
+	generateCmd.Flags().IntVar(&concurrency, "concurrency", 0, "number of concurrent LLM requests (default 4)")
+	generateCmd.Flags().IntVar(&requestsPerMinute, "rpm", 0, "maximum LLM requests per minute (0 for unlimited)")
+	generateCmd.Flags().IntVar(&tokensPerMinute, "tpm", 0, "maximum LLM tokens per minute (0 for unlimited)")
+	generateCmd.Flags().IntVar(&maxRetries, "max-retries", 0, "retries for rate-limited or failed LLM requests (default 3)")
 
 	// Mark commonly used flags
 	generateCmd.Flags().Lookup("dir").Usage = "project directory to analyze"
@@ -74,6 +150,25 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 	config.Style = style
 	config.Examples = examples
 	config.Private = private
+	config.NoAI = noAI
+	config.NoCache = noCache
+	config.Strict = strict
+	config.TypeCheck = typeCheck
+	config.Graph = graph
+	config.MermaidURL = mermaidURL
+	config.TemplateDir = templateDir
+	config.GOOS = goos
+	config.GOARCH = goarch
+	config.BuildTags = buildTags
+	config.LLMProvider = llmProvider
+	config.LLMBaseURL = llmBaseURL
+	config.LLMModel = llmModel
+	config.LLMAPIKeyEnv = llmAPIKeyEnv
+	config.LLMTemperature = llmTemperature
+	config.Concurrency = concurrency
+	config.RequestsPerMinute = requestsPerMinute
+	config.TokensPerMinute = tokensPerMinute
+	config.MaxRetries = maxRetries
 
 	// Create and run application
 	application, err := app.New(config)
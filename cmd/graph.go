@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/docaura/docaura-cli/internal/app"
+	"github.com/docaura/docaura-cli/pkg/analyzer"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+var (
+	// Graph command flags
+	graphProjectDir string
+	graphFormat     string
+	graphOutput     string
+	graphStdlib     bool
+	graphThirdParty bool
+	graphGOOS       string
+	graphGOARCH     string
+	graphBuildTags  []string
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph [flags]",
+	Short: "Render the package dependency graph",
+	Long: `Analyze the packages of a project and render the graph of their imports
+as Graphviz DOT or as a Mermaid flowchart. Module packages are drawn as boxes,
+dashed under internal/ directories; standard library and third-party packages
+are drawn in their own colors. Import cycles are highlighted in red and
+reported as warnings.`,
+	Args: cobra.NoArgs,
+	RunE: runGraph,
+	Example: `  # Print the graph of the current project as DOT
+  docaura graph
+
+  # Render it with Graphviz
+  docaura graph | dot -Tsvg -o deps.svg
+
+  # Write a Mermaid flowchart including standard library imports
+  docaura graph --format mermaid --std --output deps.mmd
+
+  # Only show imports between the project's own packages
+  docaura graph --third-party=false`,
+}
+
+func init() {
+	rootCmd.AddCommand(graphCmd)
+
+	// Command-specific flags
+	graphCmd.Flags().StringVarP(&graphProjectDir, "dir", "d", ".", "project directory to analyze")
+	graphCmd.Flags().StringVarP(&graphFormat, "format", "f", "dot", "graph format: dot or mermaid")
+	graphCmd.Flags().StringVarP(&graphOutput, "output", "o", "", "file to write the graph to (default stdout)")
+	graphCmd.Flags().BoolVar(&graphStdlib, "std", false, "include standard library imports")
+	graphCmd.Flags().BoolVar(&graphThirdParty, "third-party", true, "include third-party imports")
+	graphCmd.Flags().StringVar(&graphGOOS, "goos", "", "target operating system for build constraints (default host GOOS)")
+	graphCmd.Flags().StringVar(&graphGOARCH, "goarch", "", "target architecture for build constraints (default host GOARCH)")
+	graphCmd.Flags().StringSliceVar(&graphBuildTags, "tags", nil, "comma-separated build tags to satisfy, as with go build -tags")
+}
+
+func runGraph(cmd *cobra.Command, args []string) error {
+	config := GetGlobalConfig()
+	config.ProjectDir = graphProjectDir
+	config.GOOS = graphGOOS
+	config.GOARCH = graphGOARCH
+	config.BuildTags = graphBuildTags
+
+	// The graph only needs the analyzer
+	config.NoAI = true
+
+	application, err := app.New(config)
+	if err != nil {
+		return fmt.Errorf("failed to create application: %w", err)
+	}
+
+	var external []analyzer.ImportKind
+	if graphStdlib {
+		external = append(external, analyzer.ImportStdlib)
+	}
+	if graphThirdParty {
+		external = append(external, analyzer.ImportThirdParty)
+	}
+
+	content, err := application.Graph(graphFormat, external...)
+	if err != nil {
+		return fmt.Errorf("render graph: %w", err)
+	}
+
+	if graphOutput == "" {
+		fmt.Print(content)
+		return nil
+	}
+	if err := os.WriteFile(graphOutput, []byte(content), 0644); err != nil {
+		return fmt.Errorf("write graph: %w", err)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/docaura/docaura-cli/internal/fileutils"
 	"github.com/docaura/docaura-cli/pkg/analyzer"
@@ -9,6 +10,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // App represents the main application.
@@ -17,6 +19,9 @@ type App struct {
 	analyzer  *analyzer.Analyzer
 	generator *docgen.Generator
 	watcher   *Watcher
+	summary   *runSummary
+	index     []docgen.IndexEntry
+	outputs   map[string]string // output path -> package directory
 }
 
 // New creates a new application instance.
@@ -33,13 +38,32 @@ func New(config Config) (*App, error) {
 
 	// Create analyzer
 	analyzer := analyzer.New()
-
-	// Create generator
-	generator, err := docgen.New()
+	analyzer.SetTypeCheck(config.TypeCheck)
+	analyzer.SetIncludePrivate(config.Private)
+	analyzer.SetBuildContext(config.GOOS, config.GOARCH, config.BuildTags)
+
+	// Create generator; offline mode never constructs an LLM client
+	var generator *docgen.Generator
+	var err error
+	if config.NoAI {
+		generator, err = docgen.NewOffline()
+	} else {
+		generator, err = docgen.New(config.ToLLMConfig())
+	}
 	if err != nil {
 		return nil, fmt.Errorf("create generator: %w", err)
 	}
 
+	if config.TemplateDir != "" {
+		if err := generator.LoadTemplates(config.TemplateDir); err != nil {
+			return nil, fmt.Errorf("load templates: %w", err)
+		}
+	}
+
+	if !config.NoAI && !config.NoCache {
+		generator.SetCache(docgen.NewCache(config.CacheDir))
+	}
+
 	app := &App{
 		config:    config,
 		analyzer:  analyzer,
@@ -71,19 +95,43 @@ func (a *App) Run() error {
 	return a.generateOnce()
 }
 
-// generateOnce generates documentation once and exits.
+// generateOnce generates documentation once and reports the enhancement
+// summary. In strict mode any failed enhancement fails the run.
 func (a *App) generateOnce() error {
+	a.summary = newRunSummary()
+	a.index = nil
+	a.outputs = make(map[string]string)
+
+	var err error
 	if a.config.PackageName != "" {
-		return a.generateSinglePackage()
+		err = a.generateSinglePackage()
+	} else {
+		err = a.generateAllPackages()
+	}
+
+	a.summary.report()
+	if err != nil {
+		return err
 	}
 
-	return a.generateAllPackages()
+	if a.config.Strict && a.summary.failed() > 0 {
+		return fmt.Errorf("%d enhancements failed (strict mode)", a.summary.failed())
+	}
+
+	return nil
 }
 
 // generateSinglePackage generates documentation for a specific package.
 func (a *App) generateSinglePackage() error {
 	packagePath := filepath.Join(a.config.ProjectDir, a.config.PackageName)
-	return a.generatePackageDocs(packagePath)
+	pkg, err := a.analyzePackage(packagePath)
+	if err != nil {
+		return err
+	}
+
+	project := analyzer.NewProject()
+	project.Add(pkg)
+	return a.generatePackageDocs(pkg, a.newSite(project))
 }
 
 // generateAllPackages generates documentation for all packages in the project.
@@ -102,47 +150,145 @@ func (a *App) generateAllPackages() error {
 		log.Printf("Found %d packages to document", len(packages))
 	}
 
-	var errors []error
-	for _, packagePath := range packages {
-		if err := a.generatePackageDocs(packagePath); err != nil {
+	// Analyze every package before generating any docs, so that each page
+	// can link to the other packages it refers to
+	project, errs := a.analyzeProject(packages)
+
+	site := a.newSite(project)
+	for _, pkg := range project.Packages {
+		if err := a.generatePackageDocs(pkg, site); err != nil {
 			if a.config.Verbose {
-				log.Printf("Error documenting package %s: %v", packagePath, err)
+				log.Printf("Error documenting package %s: %v", pkg.Path, err)
 			}
-			errors = append(errors, err)
+			errs = append(errs, err)
 		}
 	}
 
-	if len(errors) > 0 {
-		return fmt.Errorf("failed to document %d packages", len(errors))
+	if err := a.writeIndex(project); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to document %d packages", len(errs))
 	}
 
 	return nil
 }
 
-// generatePackageDocs generates documentation for a single package.
-func (a *App) generatePackageDocs(packagePath string) error {
+// Graph renders the dependency graph of the project's packages as Graphviz
+// DOT or Mermaid, according to format. Imports of the external kinds are
+// included as leaf nodes. Import cycles are highlighted in the graph and
+// reported in the log.
+func (a *App) Graph(format string, external ...analyzer.ImportKind) (string, error) {
+	if err := docgen.ValidateGraphFormat(format); err != nil {
+		return "", err
+	}
+
+	packages, err := fileutils.FindGoPackages(a.config.ProjectDir, a.config.ExcludeDirs)
+	if err != nil {
+		return "", fmt.Errorf("find Go packages: %w", err)
+	}
+
+	a.outputs = make(map[string]string)
+	project, errs := a.analyzeProject(packages)
+	if len(errs) > 0 {
+		return "", fmt.Errorf("analyze packages: %w", errors.Join(errs...))
+	}
+
+	graph := analyzer.NewGraph(project, external...)
+	for _, cycle := range graph.Cycles {
+		through := ""
+		for _, importPath := range cycle {
+			if graph.Node(importPath).Internal {
+				through = " through internal packages"
+				break
+			}
+		}
+		log.Printf("Warning: import cycle%s: %s", through, strings.Join(cycle, ", "))
+	}
+
+	return docgen.RenderGraph(graph, format)
+}
+
+// analyzeProject analyzes the packages in packagePaths, skipping those
+// excluded by build constraints, and returns the project made of the
+// others along with the errors of those that failed.
+func (a *App) analyzeProject(packagePaths []string) (*analyzer.Project, []error) {
+	project := analyzer.NewProject()
+	var errs []error
+	for _, packagePath := range packagePaths {
+		pkg, err := a.analyzePackage(packagePath)
+		if errors.Is(err, analyzer.ErrNoGoFiles) {
+			// Every file is excluded by build constraints for this target
+			if a.config.Verbose {
+				log.Printf("Skipping package %s: %v", packagePath, err)
+			}
+			continue
+		}
+		if err != nil {
+			if a.config.Verbose {
+				log.Printf("Error documenting package %s: %v", packagePath, err)
+			}
+			errs = append(errs, err)
+			continue
+		}
+		project.Add(pkg)
+	}
+	return project, errs
+}
+
+// analyzePackage analyzes the package in packagePath and reserves the output
+// path of its documentation, before any LLM calls are spent on it.
+func (a *App) analyzePackage(packagePath string) (*analyzer.PackageInfo, error) {
 	if a.config.Verbose {
 		log.Printf("Analyzing package: %s", packagePath)
 	}
 
-	// Analyze package
 	pkg, err := a.analyzer.AnalyzePackage(packagePath)
 	if err != nil {
-		return fmt.Errorf("analyze package %q: %w", packagePath, err)
+		return nil, fmt.Errorf("analyze package %q: %w", packagePath, err)
 	}
 
+	outputPath := a.getOutputPath(pkg)
+	if owner, taken := a.outputs[outputPath]; taken {
+		log.Printf("Output collision: %s would overwrite the docs of %s at %s", packagePath, owner, outputPath)
+		return nil, fmt.Errorf("output path %q for package %q collides with package in %q", outputPath, packagePath, owner)
+	}
+	a.outputs[outputPath] = packagePath
+
+	return pkg, nil
+}
+
+// newSite describes the pages generated for the packages of project, so that
+// they can link to each other.
+func (a *App) newSite(project *analyzer.Project) *docgen.Site {
+	pages := make(map[string]string)
+	for _, pkg := range project.Packages {
+		if pkg.ImportPath != "" {
+			pages[pkg.ImportPath] = a.relativeLink(a.config.OutputDir, a.getOutputPath(pkg))
+		}
+	}
+	return &docgen.Site{Project: project, Pages: pages}
+}
+
+// generatePackageDocs generates documentation for a single analyzed package.
+func (a *App) generatePackageDocs(pkg *analyzer.PackageInfo, site *docgen.Site) error {
+	outputPath := a.getOutputPath(pkg)
+
 	// Generate documentation
 	ctx := context.Background()
 	docgenConfig := a.config.ToDocgenConfig()
+	docgenConfig.RootPath = a.relativeLink(filepath.Dir(outputPath), a.config.OutputDir)
+	docgenConfig.Site = site
 
-	doc, err := a.generator.GeneratePackageDoc(ctx, pkg, docgenConfig)
+	result, err := a.generator.GeneratePackageDoc(ctx, pkg, docgenConfig)
 	if err != nil {
 		return fmt.Errorf("generate documentation: %w", err)
 	}
+	a.summary.add(pkg.Name, result.Outcomes)
 
 	// Write to file
-	outputPath := a.getOutputPath(pkg.Name)
-	if err := a.writeDocumentation(outputPath, doc); err != nil {
+	if err := a.writeDocumentation(outputPath, result.Content); err != nil {
 		return fmt.Errorf("write documentation: %w", err)
 	}
 
@@ -150,22 +296,94 @@ func (a *App) generatePackageDocs(packagePath string) error {
 		log.Printf("Generated documentation: %s", outputPath)
 	}
 
+	a.index = append(a.index, a.indexEntry(pkg, outputPath))
+
 	return nil
 }
 
-// getOutputPath returns the output path for a package's documentation.
-func (a *App) getOutputPath(packageName string) string {
-	var filename string
+// indexEntry builds the index entry for a documented package.
+func (a *App) indexEntry(pkg *analyzer.PackageInfo, outputPath string) docgen.IndexEntry {
+	path := pkg.ImportPath
+	if path == "" {
+		path = a.relativeLink(a.config.ProjectDir, pkg.Path)
+	}
+
+	return docgen.IndexEntry{
+		Name:        pkg.Name,
+		Path:        path,
+		Description: pkg.Description,
+		Link:        a.relativeLink(a.config.OutputDir, outputPath),
+	}
+}
+
+// writeIndex writes the index page linking every package documented in this
+// run, with the dependency graph of the project's packages if requested.
+func (a *App) writeIndex(project *analyzer.Project) error {
+	outputPath := a.getIndexPath()
+	if owner, taken := a.outputs[outputPath]; taken {
+		log.Printf("Output collision: the index would overwrite the docs of %s at %s", owner, outputPath)
+		return fmt.Errorf("index path %q collides with package in %q", outputPath, owner)
+	}
+
+	docgenConfig := a.config.ToDocgenConfig()
+	if a.config.Graph {
+		docgenConfig.Graph = analyzer.NewGraph(project, analyzer.ImportThirdParty)
+	}
+
+	content, err := a.generator.GenerateIndex(a.index, docgenConfig)
+	if err != nil {
+		return fmt.Errorf("generate index: %w", err)
+	}
+
+	if err := a.writeDocumentation(outputPath, content); err != nil {
+		return fmt.Errorf("write index: %w", err)
+	}
+
+	if a.config.Verbose {
+		log.Printf("Generated index: %s", outputPath)
+	}
+
+	return nil
+}
+
+// getOutputPath returns the output path for a package's documentation. The
+// docs tree mirrors the package directory relative to the project directory,
+// e.g. docs/pkg/docgen/index.md; the project's root package is written as
+// docs/<name>.md next to the top-level index.
+func (a *App) getOutputPath(pkg *analyzer.PackageInfo) string {
+	rel, err := filepath.Rel(a.config.ProjectDir, pkg.Path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return filepath.Join(a.config.OutputDir, pkg.Name+a.outputExt())
+	}
+
+	return filepath.Join(a.config.OutputDir, rel, "index"+a.outputExt())
+}
+
+// getIndexPath returns the output path of the top-level index page.
+func (a *App) getIndexPath() string {
+	return filepath.Join(a.config.OutputDir, "index"+a.outputExt())
+}
+
+// outputExt returns the file extension for the configured style.
+func (a *App) outputExt() string {
 	switch a.config.Style {
-	case "markdown":
-		filename = packageName + ".md"
 	case "html":
-		filename = packageName + ".html"
+		return ".html"
+	case "godoc":
+		return ".txt"
 	default:
-		filename = packageName + ".md"
+		return ".md"
 	}
+}
 
-	return filepath.Join(a.config.OutputDir, filename)
+// relativeLink returns target relative to base as a slash-separated path,
+// falling back to target itself when no relative path exists.
+func (a *App) relativeLink(base, target string) string {
+	rel, err := filepath.Rel(base, target)
+	if err != nil {
+		return filepath.ToSlash(target)
+	}
+	return filepath.ToSlash(rel)
 }
 
 // writeDocumentation writes documentation to a file.
@@ -199,6 +417,6 @@ func (a *App) runWatcher() error {
 		if a.config.Verbose {
 			log.Println("Regenerating documentation due to file changes...")
 		}
-		return a.generateAllPackages()
+		return a.generateOnce()
 	})
 }
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"errors"
+	"github.com/docaura/docaura-cli/pkg/analyzer"
+	"github.com/docaura/docaura-cli/pkg/docgen"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestApp creates an app documenting a one-package project with the given
+// LLM, bypassing the provider setup of New.
+func newTestApp(t *testing.T, llm *docgen.FakeLLM, strict bool) *App {
+	t.Helper()
+
+	projectDir := t.TempDir()
+	src := "// Package greet says hello.\npackage greet\n\nfunc Hello(name string) string { return \"Hello, \" + name }\n"
+	if err := os.WriteFile(filepath.Join(projectDir, "greet.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	generator, err := docgen.NewWithLLM(llm)
+	if err != nil {
+		t.Fatalf("create generator: %v", err)
+	}
+
+	return &App{
+		config: Config{
+			ProjectDir: projectDir,
+			OutputDir:  filepath.Join(t.TempDir(), "docs"),
+			Style:      "markdown",
+			Strict:     strict,
+		},
+		analyzer:  analyzer.New(),
+		generator: generator,
+	}
+}
+
+func TestGenerateOnceStrict(t *testing.T) {
+	providerErr := errors.New("API returned unexpected status code: 401: invalid API key")
+
+	tests := []struct {
+		name    string
+		fail    bool
+		strict  bool
+		wantErr bool
+	}{
+		{name: "failing provider", fail: true, strict: false, wantErr: false},
+		{name: "failing provider in strict mode", fail: true, strict: true, wantErr: true},
+		{name: "working provider in strict mode", fail: false, strict: true, wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm := docgen.NewFakeLLM()
+			if tt.fail {
+				llm.Fail(providerErr)
+			}
+			app := newTestApp(t, llm, tt.strict)
+
+			err := app.generateOnce()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("generateOnce() error = %v, want error %t", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "strict mode") {
+				t.Errorf("generateOnce() error = %v, want a strict mode failure", err)
+			}
+
+			if got, want := app.summary.failed() > 0, tt.fail; got != want {
+				t.Errorf("summary has failures = %t, want %t (failures: %v)", got, want, app.summary.failures)
+			}
+			// The docs are written even when the run fails
+			if _, err := os.Stat(filepath.Join(app.config.OutputDir, "greet.md")); err != nil {
+				t.Errorf("documentation not written: %v", err)
+			}
+		})
+	}
+}
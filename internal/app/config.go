@@ -20,14 +20,43 @@ type Config struct {
 	Examples    bool   `json:"examples"`
 	Private     bool   `json:"private"`
 	Verbose     bool   `json:"verbose"`
+	NoAI        bool   `json:"no_ai"`
+	NoCache     bool   `json:"no_cache"`
+	Strict      bool   `json:"strict"`
+	TypeCheck   bool   `json:"type_check"`
+	Graph       bool   `json:"graph"`
+	MermaidURL  string `json:"mermaid_url"`
+
+	// Build context used to select files by their build constraints
+	GOOS      string   `json:"goos"`
+	GOARCH    string   `json:"goarch"`
+	BuildTags []string `json:"build_tags"`
+
+	// LLM provider options
+	LLMProvider    string  `json:"llm_provider"`
+	LLMBaseURL     string  `json:"llm_base_url"`
+	LLMModel       string  `json:"llm_model"`
+	LLMAPIKeyEnv   string  `json:"llm_api_key_env"`
+	LLMTemperature float64 `json:"llm_temperature"`
+
+	// LLM pipeline options
+	Concurrency       int `json:"concurrency"`
+	RequestsPerMinute int `json:"requests_per_minute"`
+	TokensPerMinute   int `json:"tokens_per_minute"`
+	MaxRetries        int `json:"max_retries"`
 
 	// Additional config file options
 	ProjectName        string   `json:"project_name"`
 	ProjectDescription string   `json:"project_description"`
 	ExcludeDirs        []string `json:"exclude_dirs"`
 	WatchInterval      int      `json:"watch_interval_seconds"`
+	CacheDir           string   `json:"cache_dir"`
+	TemplateDir        string   `json:"template_dir"`
 }
 
+// defaultCacheDir is the cache location relative to the project directory.
+const defaultCacheDir = ".docaura/cache"
+
 // DefaultConfig returns a configuration with sensible defaults.
 func DefaultConfig() Config {
 	return Config{
@@ -87,6 +116,18 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("resolve output directory: %w", err)
 	}
 
+	if c.CacheDir == "" {
+		c.CacheDir = filepath.Join(c.ProjectDir, defaultCacheDir)
+	} else if c.CacheDir, err = filepath.Abs(c.CacheDir); err != nil {
+		return fmt.Errorf("resolve cache directory: %w", err)
+	}
+
+	if c.TemplateDir != "" {
+		if c.TemplateDir, err = filepath.Abs(c.TemplateDir); err != nil {
+			return fmt.Errorf("resolve template directory: %w", err)
+		}
+	}
+
 	// Validate style
 	validStyles := map[string]bool{
 		"markdown": true,
@@ -118,6 +159,24 @@ func (c *Config) ToDocgenConfig() docgen.Config {
 		IncludePrivate:   c.Private,
 		GenerateExamples: c.Examples,
 		Style:            c.Style,
+		DisableAI:        c.NoAI,
+		MermaidURL:       c.MermaidURL,
+	}
+}
+
+// ToLLMConfig converts the app config to a docgen.LLMConfig.
+func (c *Config) ToLLMConfig() docgen.LLMConfig {
+	return docgen.LLMConfig{
+		Provider:    c.LLMProvider,
+		BaseURL:     c.LLMBaseURL,
+		Model:       c.LLMModel,
+		APIKeyEnv:   c.LLMAPIKeyEnv,
+		Temperature: c.LLMTemperature,
+
+		Concurrency:       c.Concurrency,
+		RequestsPerMinute: c.RequestsPerMinute,
+		TokensPerMinute:   c.TokensPerMinute,
+		MaxRetries:        c.MaxRetries,
 	}
 }
 
@@ -129,10 +188,70 @@ func (c *Config) mergeFrom(other Config) {
 	if c.ProjectDescription == "" && other.ProjectDescription != "" {
 		c.ProjectDescription = other.ProjectDescription
 	}
+	if other.NoAI {
+		c.NoAI = true
+	}
+	if other.NoCache {
+		c.NoCache = true
+	}
+	if other.Strict {
+		c.Strict = true
+	}
+	if other.TypeCheck {
+		c.TypeCheck = true
+	}
+	if other.Graph {
+		c.Graph = true
+	}
+	if c.MermaidURL == "" && other.MermaidURL != "" {
+		c.MermaidURL = other.MermaidURL
+	}
+	if c.GOOS == "" && other.GOOS != "" {
+		c.GOOS = other.GOOS
+	}
+	if c.GOARCH == "" && other.GOARCH != "" {
+		c.GOARCH = other.GOARCH
+	}
+	if len(c.BuildTags) == 0 && len(other.BuildTags) > 0 {
+		c.BuildTags = other.BuildTags
+	}
+	if c.CacheDir == "" && other.CacheDir != "" {
+		c.CacheDir = other.CacheDir
+	}
+	if c.TemplateDir == "" && other.TemplateDir != "" {
+		c.TemplateDir = other.TemplateDir
+	}
 	if len(other.ExcludeDirs) > 0 {
 		c.ExcludeDirs = other.ExcludeDirs
 	}
 	if other.WatchInterval > 0 {
 		c.WatchInterval = other.WatchInterval
 	}
+	if c.LLMProvider == "" && other.LLMProvider != "" {
+		c.LLMProvider = other.LLMProvider
+	}
+	if c.LLMBaseURL == "" && other.LLMBaseURL != "" {
+		c.LLMBaseURL = other.LLMBaseURL
+	}
+	if c.LLMModel == "" && other.LLMModel != "" {
+		c.LLMModel = other.LLMModel
+	}
+	if c.LLMAPIKeyEnv == "" && other.LLMAPIKeyEnv != "" {
+		c.LLMAPIKeyEnv = other.LLMAPIKeyEnv
+	}
+	if c.LLMTemperature == 0 && other.LLMTemperature != 0 {
+		c.LLMTemperature = other.LLMTemperature
+	}
+	if c.Concurrency == 0 && other.Concurrency > 0 {
+		c.Concurrency = other.Concurrency
+	}
+	if c.RequestsPerMinute == 0 && other.RequestsPerMinute > 0 {
+		c.RequestsPerMinute = other.RequestsPerMinute
+	}
+	if c.TokensPerMinute == 0 && other.TokensPerMinute > 0 {
+		c.TokensPerMinute = other.TokensPerMinute
+	}
+	if c.MaxRetries == 0 && other.MaxRetries > 0 {
+		c.MaxRetries = other.MaxRetries
+	}
 }
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"fmt"
+	"github.com/docaura/docaura-cli/pkg/docgen"
+	"log"
+)
+
+// runSummary collects LLM enhancement outcomes across the packages of a run.
+type runSummary struct {
+	counts   map[docgen.OutcomeStatus]int
+	failures []string
+}
+
+// newRunSummary creates an empty run summary.
+func newRunSummary() *runSummary {
+	return &runSummary{
+		counts: make(map[docgen.OutcomeStatus]int),
+	}
+}
+
+// add records the outcomes of a single package.
+func (s *runSummary) add(packageName string, outcomes []docgen.Outcome) {
+	for _, o := range outcomes {
+		s.counts[o.Status]++
+		if o.Status == docgen.OutcomeFailed {
+			s.failures = append(s.failures, fmt.Sprintf("%s: %s (%s): %s", packageName, o.Symbol, o.Task, o.Reason))
+		}
+	}
+}
+
+// failed returns the number of failed enhancements.
+func (s *runSummary) failed() int {
+	return s.counts[docgen.OutcomeFailed]
+}
+
+// report logs the summary. Nothing is logged when no enhancements ran.
+func (s *runSummary) report() {
+	total := 0
+	for _, n := range s.counts {
+		total += n
+	}
+	if total == 0 {
+		return
+	}
+
+	log.Printf("Enhancement summary: %d enhanced, %d cached, %d skipped, %d failed",
+		s.counts[docgen.OutcomeEnhanced],
+		s.counts[docgen.OutcomeCached],
+		s.counts[docgen.OutcomeSkipped],
+		s.counts[docgen.OutcomeFailed])
+
+	for _, failure := range s.failures {
+		log.Printf("  failed %s", failure)
+	}
+}
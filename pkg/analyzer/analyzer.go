@@ -3,21 +3,52 @@ package analyzer
 import (
 	"fmt"
 	"go/ast"
+	"go/build"
 	"go/doc"
-	"go/parser"
 	"go/token"
+	"go/types"
+	"sort"
 	"strings"
 )
 
 // Analyzer analyzes Go source code and extracts documentation information.
 type Analyzer struct {
-	fset *token.FileSet
+	fset      *token.FileSet
+	typeCheck bool
+	mode      doc.Mode
+	build     build.Context
+	modules   map[string]*ModuleInfo // by root directory
 }
 
-// New creates a new code analyzer instance.
+// New creates a new code analyzer instance for the host platform.
 func New() *Analyzer {
 	return &Analyzer{
-		fset: token.NewFileSet(),
+		fset:    token.NewFileSet(),
+		build:   newBuildContext("", "", nil),
+		modules: make(map[string]*ModuleInfo),
+	}
+}
+
+// SetBuildContext sets the target platform and build tags used to select
+// files by their build constraints. Empty goos or goarch default to the host.
+func (a *Analyzer) SetBuildContext(goos, goarch string, tags []string) {
+	a.build = newBuildContext(goos, goarch, tags)
+}
+
+// SetTypeCheck enables type-checked analysis, which loads each package with
+// the Go type checker to resolve qualified types, implemented interfaces,
+// constant values and promoted methods. The package must build.
+func (a *Analyzer) SetTypeCheck(enabled bool) {
+	a.typeCheck = enabled
+}
+
+// SetIncludePrivate makes the analyzer report unexported declarations and
+// methods, including methods promoted from embedded fields, alongside the
+// exported API.
+func (a *Analyzer) SetIncludePrivate(enabled bool) {
+	a.mode = 0
+	if enabled {
+		a.mode = doc.AllDecls | doc.AllMethods
 	}
 }
 
@@ -25,90 +56,169 @@ func New() *Analyzer {
 // comprehensive package information including functions, types, constants,
 // variables, and documentation.
 func (a *Analyzer) AnalyzePackage(dir string) (*PackageInfo, error) {
-	pkgs, err := parser.ParseDir(a.fset, dir, nil, parser.ParseComments)
+	pkgs, constraints, err := a.parseDir(dir)
 	if err != nil {
 		return nil, fmt.Errorf("parse directory %q: %w", dir, err)
 	}
 
-	pkg := findMainPackage(pkgs)
+	pkg := findMainPackage(pkgs, dir)
 	if pkg == nil {
-		return nil, fmt.Errorf("no Go package found in %q", dir)
+		return nil, fmt.Errorf("%w in %q for %s/%s", ErrNoGoFiles, dir, a.build.GOOS, a.build.GOARCH)
 	}
 
-	// Create documentation from parsed package
-	docPkg := doc.New(pkg, "./", 0)
+	// Create documentation from the parsed package; test files, including
+	// those of the external _test package, contribute only examples
+	files := packageFiles(pkg, pkgs[pkg.Name+"_test"])
+	constValues := evaluateConstants(files, types.SizesFor("gc", a.build.GOARCH))
+	docPkg, err := doc.NewFromFiles(a.fset, files, "./", a.mode)
+	if err != nil {
+		return nil, fmt.Errorf("read documentation in %q: %w", dir, err)
+	}
 
 	info := &PackageInfo{
 		Name:        pkg.Name,
 		Path:        dir,
 		Description: cleanDoc(docPkg.Doc),
-		Imports:     extractImports(pkg),
+		Notes:       extractNotes(docPkg.Notes),
 	}
+	info.Imports, info.ImportNames = extractImports(pkg)
 
-	a.populatePackageInfo(info, docPkg)
+	mod, err := a.findModule(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read module of %q: %w", dir, err)
+	}
+	if mod != nil {
+		info.Module = mod
+		info.ImportPath = importPath(mod, dir)
+	}
+
+	src := newSourcePrinter(a.fset, files)
+	a.populatePackageInfo(info, docPkg, src, constraints)
+	info.Examples = analyzeExamples(docPkg.Examples, src)
+	applyConstValues(info, constValues)
+
+	if a.typeCheck {
+		if err := a.applyTypeInfo(info, dir, goFiles(pkg)); err != nil {
+			return nil, fmt.Errorf("type-check %q: %w", dir, err)
+		}
+	}
+
+	detectEnums(info)
 
 	return info, nil
 }
 
 // populatePackageInfo populates the PackageInfo with data from the doc.Package.
-func (a *Analyzer) populatePackageInfo(info *PackageInfo, docPkg *doc.Package) {
+func (a *Analyzer) populatePackageInfo(info *PackageInfo, docPkg *doc.Package, src *sourcePrinter, constraints *buildConstraints) {
 	// Analyze functions
 	for _, fn := range docPkg.Funcs {
-		fnInfo := a.analyzeFunctionDecl(fn)
+		fnInfo := a.analyzeFunctionDecl(fn, src)
+		fnInfo.BuildConstraint = constraints.of(fn.Decl)
 		info.Functions = append(info.Functions, fnInfo)
 	}
 
 	// Analyze types and their methods
 	for _, typ := range docPkg.Types {
-		typeInfo := a.analyzeTypeDecl(typ)
-		info.Types = append(info.Types, typeInfo)
+		typeInfo := a.analyzeTypeDecl(typ, src)
+		if typ.Decl != nil {
+			typeInfo.Decl = src.print(typ.Decl)
+			typeInfo.BuildConstraint = constraints.of(typ.Decl)
+		}
+
+		// Add constructors and other associated functions
+		for _, fn := range typ.Funcs {
+			fnInfo := a.analyzeFunctionDecl(fn, src)
+			fnInfo.AssociatedType = typ.Name
+			fnInfo.BuildConstraint = constraints.of(fn.Decl)
+			info.Functions = append(info.Functions, fnInfo)
+			typeInfo.Funcs = append(typeInfo.Funcs, fn.Name)
+		}
 
 		// Add methods to functions list
 		for _, method := range typ.Methods {
-			methodInfo := a.analyzeFunctionDecl(method)
+			if promotedFromExported(method) {
+				continue
+			}
+			methodInfo := a.analyzeFunctionDecl(method, src)
 			methodInfo.IsMethod = true
 			methodInfo.Receiver = typ.Name
+			methodInfo.BuildConstraint = constraints.of(method.Decl)
 			info.Functions = append(info.Functions, methodInfo)
 		}
+
+		// Add constants and variables declared with this type
+		for _, c := range typ.Consts {
+			info.Constants = append(info.Constants, a.analyzeConstantDecl(c, src, constraints)...)
+			info.ConstDecls = append(info.ConstDecls, analyzeDeclBlock(c, typ.Name, src, constraints))
+			typeInfo.Consts = append(typeInfo.Consts, c.Names...)
+		}
+		for _, v := range typ.Vars {
+			info.Variables = append(info.Variables, a.analyzeVariableDecl(v, src, constraints)...)
+			info.VarDecls = append(info.VarDecls, analyzeDeclBlock(v, typ.Name, src, constraints))
+			typeInfo.Vars = append(typeInfo.Vars, v.Names...)
+		}
+
+		info.Types = append(info.Types, typeInfo)
 	}
 
 	// Analyze constants
 	for _, c := range docPkg.Consts {
-		constInfo := a.analyzeConstantDecl(c)
+		constInfo := a.analyzeConstantDecl(c, src, constraints)
 		info.Constants = append(info.Constants, constInfo...)
+		info.ConstDecls = append(info.ConstDecls, analyzeDeclBlock(c, "", src, constraints))
 	}
 
 	// Analyze variables
 	for _, v := range docPkg.Vars {
-		varInfo := a.analyzeVariableDecl(v)
+		varInfo := a.analyzeVariableDecl(v, src, constraints)
 		info.Variables = append(info.Variables, varInfo...)
+		info.VarDecls = append(info.VarDecls, analyzeDeclBlock(v, "", src, constraints))
+	}
+}
+
+// analyzeDeclBlock captures a const or var declaration block as written in the source.
+func analyzeDeclBlock(v *doc.Value, typeName string, src *sourcePrinter, constraints *buildConstraints) DeclInfo {
+	description, deprecation := extractDeprecation(v.Doc)
+	return DeclInfo{
+		Names:           v.Names,
+		Source:          src.print(v.Decl),
+		Description:     cleanDoc(description),
+		Type:            typeName,
+		Deprecation:     deprecation,
+		BuildConstraint: constraints.of(v.Decl),
 	}
 }
 
 // analyzeFunctionDecl analyzes a function declaration and returns function information.
-func (a *Analyzer) analyzeFunctionDecl(fn *doc.Func) FunctionInfo {
+func (a *Analyzer) analyzeFunctionDecl(fn *doc.Func, src *sourcePrinter) FunctionInfo {
+	description, deprecation := extractDeprecation(fn.Doc)
 	info := FunctionInfo{
 		Name:        fn.Name,
-		Description: cleanDoc(fn.Doc),
+		Description: cleanDoc(description),
 		IsExported:  ast.IsExported(fn.Name),
-		Examples:    extractExamplesFromDoc(fn.Doc),
+		Examples:    append(analyzeExamples(fn.Examples, src), extractExamplesFromDoc(fn.Doc)...),
+		Deprecation: deprecation,
 	}
 
 	if fn.Decl != nil && fn.Decl.Type != nil {
-		info.Signature = a.getFunctionSignature(fn.Decl)
-		info.Parameters = extractParameters(fn.Decl.Type.Params)
-		info.Returns = extractReturns(fn.Decl.Type.Results)
+		info.Signature = src.signature(fn.Decl)
+		info.TypeParams = extractTypeParams(fn.Decl.Type.TypeParams, src)
+		info.Parameters = extractParameters(fn.Decl.Type.Params, src)
+		info.Returns = extractReturns(fn.Decl.Type.Results, src)
 	}
 
 	return info
 }
 
 // analyzeTypeDecl analyzes a type declaration and returns type information.
-func (a *Analyzer) analyzeTypeDecl(typ *doc.Type) TypeInfo {
+func (a *Analyzer) analyzeTypeDecl(typ *doc.Type, src *sourcePrinter) TypeInfo {
+	description, deprecation := extractDeprecation(typ.Doc)
 	info := TypeInfo{
 		Name:        typ.Name,
-		Description: cleanDoc(typ.Doc),
+		Description: cleanDoc(description),
 		IsExported:  ast.IsExported(typ.Name),
+		Examples:    analyzeExamples(typ.Examples, src),
+		Deprecation: deprecation,
 	}
 
 	if typ.Decl != nil {
@@ -118,14 +228,23 @@ func (a *Analyzer) analyzeTypeDecl(typ *doc.Type) TypeInfo {
 				continue
 			}
 			info.Kind = getTypeKind(ts.Type)
-			if structType, ok := ts.Type.(*ast.StructType); ok {
-				info.Fields = extractStructFields(structType)
+			info.TypeParams = extractTypeParams(ts.TypeParams, src)
+			switch t := ts.Type.(type) {
+			case *ast.StructType:
+				info.Fields = extractStructFields(t, src)
+			case *ast.InterfaceType:
+				info.Constraint = extractTypeSet(t, src)
+				info.InterfaceMethods = extractInterfaceMethods(t, src)
+				info.Embeds = extractEmbeds(t, src)
 			}
 		}
 	}
 
 	// Extract method names
 	for _, method := range typ.Methods {
+		if promotedFromExported(method) {
+			continue
+		}
 		info.Methods = append(info.Methods, method.Name)
 	}
 
@@ -133,7 +252,7 @@ func (a *Analyzer) analyzeTypeDecl(typ *doc.Type) TypeInfo {
 }
 
 // analyzeConstantDecl analyzes a constant declaration and returns constant information.
-func (a *Analyzer) analyzeConstantDecl(c *doc.Value) []ConstantInfo {
+func (a *Analyzer) analyzeConstantDecl(c *doc.Value, src *sourcePrinter, constraints *buildConstraints) []ConstantInfo {
 	var constants []ConstantInfo
 
 	for _, spec := range c.Decl.Specs {
@@ -142,19 +261,35 @@ func (a *Analyzer) analyzeConstantDecl(c *doc.Value) []ConstantInfo {
 			continue
 		}
 
+		// Members of a block are described by their own comments, preferring
+		// the line comment; the doc of the block describes the group as a
+		// whole. A single declaration without parentheses is described by
+		// its doc.
+		var description string
+		if text := vs.Comment.Text(); text != "" {
+			description = text
+		} else if text := vs.Doc.Text(); text != "" {
+			description = text
+		} else if !c.Decl.Lparen.IsValid() {
+			description = c.Doc
+		}
+		description, deprecation := extractDeprecation(description)
+
 		for i, name := range vs.Names {
 			constInfo := ConstantInfo{
-				Name:        name.Name,
-				Description: cleanDoc(c.Doc),
-				IsExported:  ast.IsExported(name.Name),
+				Name:            name.Name,
+				Description:     cleanDoc(description),
+				Deprecation:     deprecation,
+				IsExported:      ast.IsExported(name.Name),
+				BuildConstraint: constraints.of(c.Decl),
 			}
 
 			if vs.Type != nil {
-				constInfo.Type = typeToString(vs.Type)
+				constInfo.Type = src.expr(vs.Type)
 			}
 
 			if i < len(vs.Values) && vs.Values[i] != nil {
-				constInfo.Value = exprToString(vs.Values[i])
+				constInfo.Value = src.expr(vs.Values[i])
 			}
 
 			constants = append(constants, constInfo)
@@ -165,8 +300,9 @@ func (a *Analyzer) analyzeConstantDecl(c *doc.Value) []ConstantInfo {
 }
 
 // analyzeVariableDecl analyzes a variable declaration and returns variable information.
-func (a *Analyzer) analyzeVariableDecl(v *doc.Value) []VariableInfo {
+func (a *Analyzer) analyzeVariableDecl(v *doc.Value, src *sourcePrinter, constraints *buildConstraints) []VariableInfo {
 	var variables []VariableInfo
+	description, deprecation := extractDeprecation(v.Doc)
 
 	for _, spec := range v.Decl.Specs {
 		vs, ok := spec.(*ast.ValueSpec)
@@ -176,13 +312,15 @@ func (a *Analyzer) analyzeVariableDecl(v *doc.Value) []VariableInfo {
 
 		for _, name := range vs.Names {
 			varInfo := VariableInfo{
-				Name:        name.Name,
-				Description: cleanDoc(v.Doc),
-				IsExported:  ast.IsExported(name.Name),
+				Name:            name.Name,
+				Description:     cleanDoc(description),
+				IsExported:      ast.IsExported(name.Name),
+				Deprecation:     deprecation,
+				BuildConstraint: constraints.of(v.Decl),
 			}
 
 			if vs.Type != nil {
-				varInfo.Type = typeToString(vs.Type)
+				varInfo.Type = src.expr(vs.Type)
 			}
 
 			variables = append(variables, varInfo)
@@ -192,48 +330,41 @@ func (a *Analyzer) analyzeVariableDecl(v *doc.Value) []VariableInfo {
 	return variables
 }
 
-// getFunctionSignature builds a function signature string from an AST function declaration.
-func (a *Analyzer) getFunctionSignature(decl *ast.FuncDecl) string {
-	var parts []string
-
-	parts = append(parts, "func")
-
-	// Add receiver if it's a method
-	if decl.Recv != nil {
-		recv := fieldListToString(decl.Recv)
-		parts = append(parts, fmt.Sprintf("(%s)", recv))
-	}
-
-	// Add function name
-	parts = append(parts, decl.Name.Name)
-
-	// Add parameters
-	if decl.Type.Params != nil {
-		params := fieldListToString(decl.Type.Params)
-		parts = append(parts, fmt.Sprintf("(%s)", params))
-	} else {
-		parts = append(parts, "()")
-	}
+// promotedFromExported reports whether a method is promoted from an exported
+// embedded type, which documents the method itself. Such methods are only
+// reported by go/doc in doc.AllMethods mode.
+func promotedFromExported(method *doc.Func) bool {
+	return method.Level > 0 && ast.IsExported(strings.TrimPrefix(method.Orig, "*"))
+}
 
-	// Add return types
-	if decl.Type.Results != nil {
-		results := fieldListToString(decl.Type.Results)
-		if len(decl.Type.Results.List) == 1 && len(decl.Type.Results.List[0].Names) == 0 {
-			parts = append(parts, results)
-		} else {
-			parts = append(parts, fmt.Sprintf("(%s)", results))
+// packageFiles returns the files of pkg followed by those of its external
+// test package, if any, sorted by file name within each package.
+func packageFiles(pkg, testPkg *ast.Package) []*ast.File {
+	var files []*ast.File
+	for _, p := range []*ast.Package{pkg, testPkg} {
+		if p == nil {
+			continue
+		}
+		names := make([]string, 0, len(p.Files))
+		for name := range p.Files {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			files = append(files, p.Files[name])
 		}
 	}
-
-	return strings.Join(parts, " ")
+	return files
 }
 
-// findMainPackage finds the main (non-test) package from a map of packages.
-func findMainPackage(pkgs map[string]*ast.Package) *ast.Package {
-	for name, pkg := range pkgs {
-		if !strings.HasSuffix(name, "_test") {
-			return pkg
+// goFiles returns the paths of the non-test files of pkg.
+func goFiles(pkg *ast.Package) []string {
+	var paths []string
+	for name := range pkg.Files {
+		if !strings.HasSuffix(name, "_test.go") {
+			paths = append(paths, name)
 		}
 	}
-	return nil
+	sort.Strings(paths)
+	return paths
 }
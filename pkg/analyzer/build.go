@@ -0,0 +1,221 @@
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/build/constraint"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrNoGoFiles is returned for directories without Go files that satisfy the
+// build context, such as a directory of Windows-only files analyzed for Linux.
+var ErrNoGoFiles = errors.New("no buildable Go files")
+
+// knownOS lists the GOOS values recognized in file name suffixes such as
+// "_linux.go", as in go/build.
+var knownOS = map[string]bool{
+	"aix": true, "android": true, "darwin": true, "dragonfly": true,
+	"freebsd": true, "hurd": true, "illumos": true, "ios": true, "js": true,
+	"linux": true, "nacl": true, "netbsd": true, "openbsd": true, "plan9": true,
+	"solaris": true, "wasip1": true, "windows": true, "zos": true,
+}
+
+// knownArch lists the GOARCH values recognized in file name suffixes such as
+// "_arm64.go", as in go/build.
+var knownArch = map[string]bool{
+	"386": true, "amd64": true, "amd64p32": true, "arm": true, "armbe": true,
+	"arm64": true, "arm64be": true, "loong64": true, "mips": true, "mipsle": true,
+	"mips64": true, "mips64le": true, "mips64p32": true, "mips64p32le": true,
+	"ppc": true, "ppc64": true, "ppc64le": true, "riscv": true, "riscv64": true,
+	"s390": true, "s390x": true, "sparc": true, "sparc64": true, "wasm": true,
+}
+
+// newBuildContext returns the build context for the given target. Empty goos
+// or goarch default to the host. Cgo is enabled so that cgo files are
+// documented even when cross-analyzing.
+func newBuildContext(goos, goarch string, tags []string) build.Context {
+	ctxt := build.Default
+	if goos != "" {
+		ctxt.GOOS = goos
+	}
+	if goarch != "" {
+		ctxt.GOARCH = goarch
+	}
+	ctxt.BuildTags = tags
+	ctxt.CgoEnabled = true
+	return ctxt
+}
+
+// buildConstraints maps the files of a package to their build constraints.
+type buildConstraints struct {
+	fset   *token.FileSet
+	byFile map[string]string
+}
+
+// of returns the build constraint of the file declaring node, or an empty
+// string when the file is built unconditionally.
+func (c *buildConstraints) of(node ast.Node) string {
+	if node == nil {
+		return ""
+	}
+	return c.byFile[c.fset.Position(node.Pos()).Filename]
+}
+
+// parseDir parses the Go files of dir that satisfy the build context, grouped
+// by package name, and records the build constraint of each file. Unlike
+// parser.ParseDir, files excluded by //go:build lines or by GOOS and GOARCH
+// file name suffixes are skipped, so platform-specific variants of the same
+// declaration are not merged.
+func (a *Analyzer) parseDir(dir string) (map[string]*ast.Package, *buildConstraints, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pkgs := make(map[string]*ast.Package)
+	constraints := &buildConstraints{fset: a.fset, byFile: make(map[string]string)}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") {
+			continue
+		}
+
+		match, err := a.build.MatchFile(dir, name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("match build constraints of %q: %w", name, err)
+		}
+		if !match {
+			continue
+		}
+
+		filename := filepath.Join(dir, name)
+		file, err := parser.ParseFile(a.fset, filename, nil, parser.ParseComments)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		pkg, ok := pkgs[file.Name.Name]
+		if !ok {
+			pkg = &ast.Package{Name: file.Name.Name, Files: make(map[string]*ast.File)}
+			pkgs[file.Name.Name] = pkg
+		}
+		pkg.Files[filename] = file
+
+		if expr := fileConstraint(name, file); expr != "" {
+			constraints.byFile[filename] = expr
+		}
+	}
+
+	return pkgs, constraints, nil
+}
+
+// fileConstraint returns the build constraint of a file, combining the GOOS
+// and GOARCH implied by its name with its //go:build line, e.g.
+// "linux && cgo" for a poll_linux.go file tagged "//go:build cgo". It returns
+// an empty string for files that are always built.
+func fileConstraint(name string, file *ast.File) string {
+	var build constraint.Expr
+	for _, group := range file.Comments {
+		if group.Pos() >= file.Package {
+			break
+		}
+		for _, c := range group.List {
+			if !constraint.IsGoBuild(c.Text) {
+				continue
+			}
+			if x, err := constraint.Parse(c.Text); err == nil {
+				build = andExpr(build, x)
+			}
+		}
+	}
+
+	// Tags implied by the name come first, unless the //go:build line
+	// already requires them, as in "//go:build linux" in foo_linux.go
+	var expr constraint.Expr
+	for _, tag := range impliedTags(name) {
+		if !requiresTag(build, tag) {
+			expr = andExpr(expr, &constraint.TagExpr{Tag: tag})
+		}
+	}
+	if build != nil {
+		expr = andExpr(expr, build)
+	}
+
+	if expr == nil {
+		return ""
+	}
+	return expr.String()
+}
+
+// requiresTag reports whether x can only be satisfied when tag is set,
+// because tag is one of the terms of its top-level conjunction.
+func requiresTag(x constraint.Expr, tag string) bool {
+	switch x := x.(type) {
+	case *constraint.TagExpr:
+		return x.Tag == tag
+	case *constraint.AndExpr:
+		return requiresTag(x.X, tag) || requiresTag(x.Y, tag)
+	default:
+		return false
+	}
+}
+
+// andExpr returns x && y, or y alone when x is nil.
+func andExpr(x, y constraint.Expr) constraint.Expr {
+	if x == nil {
+		return y
+	}
+	return &constraint.AndExpr{X: x, Y: y}
+}
+
+// impliedTags returns the GOOS and GOARCH implied by a file name following
+// the name_GOOS_GOARCH.go convention of go/build.
+func impliedTags(name string) []string {
+	name = strings.TrimSuffix(strings.TrimSuffix(name, ".go"), "_test")
+
+	// The part before the first underscore never implies a tag
+	parts := strings.Split(name, "_")[1:]
+	n := len(parts)
+	switch {
+	case n >= 2 && knownOS[parts[n-2]] && knownArch[parts[n-1]]:
+		return []string{parts[n-2], parts[n-1]}
+	case n >= 1 && knownOS[parts[n-1]]:
+		return []string{parts[n-1]}
+	case n >= 1 && knownArch[parts[n-1]]:
+		return []string{parts[n-1]}
+	default:
+		return nil
+	}
+}
+
+// findMainPackage picks the package to document among those parsed from dir.
+// Test packages are ignored. When several packages remain, the one named
+// after the directory wins, and otherwise the first in alphabetical order,
+// so the choice does not change between runs.
+func findMainPackage(pkgs map[string]*ast.Package, dir string) *ast.Package {
+	var names []string
+	for name := range pkgs {
+		if !strings.HasSuffix(name, "_test") {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+
+	base := filepath.Base(dir)
+	for _, name := range names {
+		if name == base {
+			return pkgs[name]
+		}
+	}
+	return pkgs[names[0]]
+}
@@ -0,0 +1,70 @@
+package analyzer
+
+import (
+	"go/parser"
+	"go/token"
+	"slices"
+	"testing"
+)
+
+func TestImpliedTags(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{name: "file.go"},
+		{name: "linux.go"},
+		{name: "file_linux.go", want: []string{"linux"}},
+		{name: "file_amd64.go", want: []string{"amd64"}},
+		{name: "file_linux_amd64.go", want: []string{"linux", "amd64"}},
+		{name: "file_linux_test.go", want: []string{"linux"}},
+		{name: "file_windows_arm64_test.go", want: []string{"windows", "arm64"}},
+		{name: "file_amd64_linux.go", want: []string{"linux"}},
+		{name: "file_other.go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := impliedTags(tt.name); !slices.Equal(got, tt.want) {
+				t.Errorf("impliedTags(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileConstraint(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "file.go"},
+		{name: "file_linux.go", want: "linux"},
+		{name: "file_linux_amd64.go", want: "linux && amd64"},
+		{name: "file.go", header: "//go:build cgo", want: "cgo"},
+		{name: "file_linux.go", header: "//go:build cgo", want: "linux && cgo"},
+		{name: "file_linux.go", header: "//go:build linux", want: "linux"},
+		{name: "file_linux.go", header: "//go:build linux && cgo", want: "linux && cgo"},
+		{name: "file_linux_amd64.go", header: "//go:build amd64", want: "linux && amd64"},
+		{name: "file_linux.go", header: "//go:build linux || darwin", want: "linux && (linux || darwin)"},
+		{name: "file_linux.go", header: "//go:build !linux", want: "linux && !linux"},
+		{name: "file.go", header: "// Package p is built everywhere.", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" "+tt.header, func(t *testing.T) {
+			src := "package p\n"
+			if tt.header != "" {
+				src = tt.header + "\n\n" + src
+			}
+			file, err := parser.ParseFile(token.NewFileSet(), tt.name, src, parser.ParseComments)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+
+			if got := fileConstraint(tt.name, file); got != tt.want {
+				t.Errorf("fileConstraint(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
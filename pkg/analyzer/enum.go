@@ -0,0 +1,359 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// constValue is the value of a package-level constant computed from syntax.
+type constValue struct {
+	typ   string // declared type, including one repeated from a previous spec
+	value constant.Value
+}
+
+// evaluateConstants computes the values of the package-level constants in
+// files without type information. It must run before go/doc filters the
+// declarations, since removing unexported specs would shift iota. Constants
+// whose values depend on other packages or unsupported expressions are
+// reported with a nil value. The sizes of the target architecture, if known,
+// give the width of uint and uintptr.
+func evaluateConstants(files []*ast.File, sizes types.Sizes) map[string]constValue {
+	values := make(map[string]constValue)
+	underlying := make(map[string]string)
+	var pending []pendingConst
+
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			if gen.Tok == token.TYPE {
+				for _, spec := range gen.Specs {
+					ts := spec.(*ast.TypeSpec)
+					if ident, ok := ts.Type.(*ast.Ident); ok && ts.TypeParams == nil {
+						underlying[ts.Name.Name] = ident.Name
+					}
+				}
+				continue
+			}
+			if gen.Tok != token.CONST {
+				continue
+			}
+
+			var typ ast.Expr
+			var exprs []ast.Expr
+			for iota, spec := range gen.Specs {
+				vs := spec.(*ast.ValueSpec)
+				if vs.Type != nil || len(vs.Values) > 0 {
+					typ, exprs = vs.Type, vs.Values
+				}
+				for i, name := range vs.Names {
+					if name.Name == "_" || i >= len(exprs) {
+						continue
+					}
+					c := pendingConst{name: name.Name, expr: exprs[i], iota: iota}
+					if typ != nil {
+						c.typ = types.ExprString(typ)
+					}
+					pending = append(pending, c)
+				}
+			}
+		}
+	}
+
+	// Constants may refer to constants declared later or in other files, so
+	// evaluate until no more values can be resolved
+	eval := &constEvaluator{values: values, underlying: underlying, sizes: sizes}
+	for progress := true; progress && len(pending) > 0; {
+		progress = false
+		remaining := pending[:0]
+		for _, c := range pending {
+			value := eval.eval(c.expr, c.iota)
+			if value == nil {
+				remaining = append(remaining, c)
+				continue
+			}
+			values[c.name] = constValue{typ: c.typ, value: value}
+			progress = true
+		}
+		pending = remaining
+	}
+
+	for _, c := range pending {
+		values[c.name] = constValue{typ: c.typ}
+	}
+
+	return values
+}
+
+// pendingConst is a constant whose value has not been computed yet.
+type pendingConst struct {
+	name string
+	typ  string
+	expr ast.Expr
+	iota int
+}
+
+// constEvaluator evaluates constant expressions made of literals, iota,
+// arithmetic, conversions and references to constants computed earlier.
+type constEvaluator struct {
+	values     map[string]constValue
+	underlying map[string]string // basic type underlying each package type defined as one, e.g. "uint8"
+	sizes      types.Sizes       // may be nil
+}
+
+// eval returns the value of expr, or nil if it cannot be computed.
+func (e *constEvaluator) eval(expr ast.Expr, iota int) constant.Value {
+	switch x := expr.(type) {
+	case *ast.BasicLit:
+		value := constant.MakeFromLiteral(x.Value, x.Kind, 0)
+		if value.Kind() == constant.Unknown {
+			return nil
+		}
+		return value
+	case *ast.Ident:
+		switch x.Name {
+		case "iota":
+			return constant.MakeInt64(int64(iota))
+		case "true", "false":
+			return constant.MakeBool(x.Name == "true")
+		}
+		if c, ok := e.values[x.Name]; ok {
+			return c.value
+		}
+		return nil
+	case *ast.ParenExpr:
+		return e.eval(x.X, iota)
+	case *ast.UnaryExpr:
+		v := e.eval(x.X, iota)
+		if v == nil {
+			return nil
+		}
+		// The complement of an unsigned value depends on the width of its
+		// type: ^uint8(0) is 255
+		var prec uint
+		if x.Op == token.XOR {
+			bits, ok := e.unsignedBits(e.typeOf(x.X))
+			if !ok {
+				return nil
+			}
+			prec = bits
+		}
+		return constant.UnaryOp(x.Op, v, prec)
+	case *ast.BinaryExpr:
+		return e.evalBinary(x, iota)
+	case *ast.CallExpr:
+		// Conversions such as Level(1) keep the value of their argument
+		if len(x.Args) != 1 {
+			return nil
+		}
+		switch fun := x.Fun.(type) {
+		case *ast.Ident:
+			if constBuiltins[fun.Name] {
+				return nil
+			}
+		case *ast.SelectorExpr:
+			if types.ExprString(fun) == "unsafe.Sizeof" || types.ExprString(fun) == "unsafe.Alignof" ||
+				types.ExprString(fun) == "unsafe.Offsetof" {
+				return nil
+			}
+		default:
+			return nil
+		}
+		return e.eval(x.Args[0], iota)
+	default:
+		return nil
+	}
+}
+
+// typeOf returns the name of the type of a constant expression, or an empty
+// string for untyped expressions. Types from other packages are returned
+// qualified, e.g. "time.Duration".
+func (e *constEvaluator) typeOf(expr ast.Expr) string {
+	switch x := expr.(type) {
+	case *ast.Ident:
+		if c, ok := e.values[x.Name]; ok {
+			return c.typ
+		}
+		return ""
+	case *ast.ParenExpr:
+		return e.typeOf(x.X)
+	case *ast.UnaryExpr:
+		return e.typeOf(x.X)
+	case *ast.BinaryExpr:
+		switch x.Op {
+		case token.SHL, token.SHR:
+			return e.typeOf(x.X)
+		case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+			return ""
+		}
+		if typ := e.typeOf(x.X); typ != "" {
+			return typ
+		}
+		return e.typeOf(x.Y)
+	case *ast.CallExpr:
+		if ident, ok := x.Fun.(*ast.Ident); ok && constBuiltins[ident.Name] {
+			return ""
+		}
+		return types.ExprString(x.Fun)
+	default:
+		return ""
+	}
+}
+
+// unsignedBits returns the width of an unsigned integer type, or 0 for other
+// types and untyped constants. It reports false when the width is unknown:
+// for types of other packages and, without target sizes, for uint and
+// uintptr.
+func (e *constEvaluator) unsignedBits(typ string) (uint, bool) {
+	// Follow definitions such as "type Flags uint8" to the basic type
+	for range 10 {
+		next, ok := e.underlying[typ]
+		if !ok {
+			break
+		}
+		typ = next
+	}
+
+	switch typ {
+	case "uint8", "byte":
+		return 8, true
+	case "uint16":
+		return 16, true
+	case "uint32":
+		return 32, true
+	case "uint64":
+		return 64, true
+	case "uint", "uintptr":
+		if e.sizes == nil {
+			return 0, false
+		}
+		return uint(e.sizes.Sizeof(types.Typ[types.Uint]) * 8), true
+	case "", "int", "int8", "int16", "int32", "int64", "rune":
+		return 0, true
+	default:
+		return 0, false
+	}
+}
+
+// constBuiltins are the builtin functions allowed in constant expressions,
+// which unlike conversions change the value of their argument.
+var constBuiltins = map[string]bool{
+	"len": true, "cap": true, "real": true, "imag": true,
+	"complex": true, "min": true, "max": true,
+}
+
+// evalBinary evaluates a binary expression.
+func (e *constEvaluator) evalBinary(x *ast.BinaryExpr, iota int) constant.Value {
+	lhs, rhs := e.eval(x.X, iota), e.eval(x.Y, iota)
+	if lhs == nil || rhs == nil {
+		return nil
+	}
+
+	switch x.Op {
+	case token.SHL, token.SHR:
+		shift, ok := constant.Uint64Val(constant.ToInt(rhs))
+		if !ok || lhs.Kind() != constant.Int {
+			return nil
+		}
+		return constant.Shift(lhs, x.Op, uint(shift))
+	case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+		return constant.MakeBool(constant.Compare(lhs, x.Op, rhs))
+	case token.QUO, token.REM:
+		if constant.Sign(rhs) == 0 {
+			return nil
+		}
+		if x.Op == token.QUO && lhs.Kind() == constant.Int && rhs.Kind() == constant.Int {
+			return constant.BinaryOp(lhs, token.QUO_ASSIGN, rhs)
+		}
+	}
+
+	if (lhs.Kind() == constant.String) != (rhs.Kind() == constant.String) {
+		return nil
+	}
+	return constant.BinaryOp(lhs, x.Op, rhs)
+}
+
+// applyConstValues records the computed value of each constant, and the
+// type repeated from a previous spec for implicitly typed constants.
+func applyConstValues(info *PackageInfo, values map[string]constValue) {
+	for i := range info.Constants {
+		c := &info.Constants[i]
+		v, ok := values[c.Name]
+		if !ok {
+			continue
+		}
+		if c.Type == "" {
+			c.Type = v.typ
+		}
+		if v.value != nil {
+			c.Evaluated = constString(v.value)
+		}
+	}
+}
+
+// constString formats a constant value. Floats use their decimal form
+// rather than an exact fraction such as "7/2".
+func constString(v constant.Value) string {
+	if v.Kind() == constant.Float {
+		return v.String()
+	}
+	return v.ExactString()
+}
+
+// detectEnums marks the types whose constant blocks form an enumeration: at
+// least two constants of the type, declared in blocks associated with it.
+func detectEnums(info *PackageInfo) {
+	constants := make(map[string]*ConstantInfo, len(info.Constants))
+	for i := range info.Constants {
+		constants[info.Constants[i].Name] = &info.Constants[i]
+	}
+
+	for i := range info.Types {
+		typ := &info.Types[i]
+
+		var members []EnumMemberInfo
+		for _, decl := range info.ConstDecls {
+			if decl.Type != typ.Name {
+				continue
+			}
+			for _, name := range decl.Names {
+				c, ok := constants[name]
+				if !ok || c.Type != typ.Name {
+					continue
+				}
+				members = append(members, EnumMemberInfo{
+					Name:        c.Name,
+					Value:       c.Evaluated,
+					Description: c.Description,
+				})
+			}
+		}
+
+		if len(members) < 2 {
+			continue
+		}
+
+		typ.Enum = &EnumInfo{
+			Members:   members,
+			HasString: hasStringMethod(info.Functions, typ.Name),
+		}
+	}
+}
+
+// hasStringMethod reports whether the named type has a String() string
+// method, making its enum values print by name.
+func hasStringMethod(fns []FunctionInfo, typeName string) bool {
+	for _, fn := range fns {
+		if fn.IsMethod && fn.Receiver == typeName && fn.Name == "String" &&
+			len(fn.Parameters) == 0 && len(fn.Returns) == 1 &&
+			strings.TrimSpace(fn.Returns[0].Type) == "string" {
+			return true
+		}
+	}
+	return false
+}
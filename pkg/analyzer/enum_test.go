@@ -0,0 +1,145 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"maps"
+	"testing"
+)
+
+func TestEvaluateConstants(t *testing.T) {
+	amd64 := types.SizesFor("gc", "amd64")
+
+	tests := []struct {
+		name  string
+		src   string
+		sizes types.Sizes
+		want  map[string]string // value of each constant, empty when unevaluated
+		types map[string]string // declared or repeated type, when checked
+	}{
+		{
+			name: "iota",
+			src:  "const (\n\tA = iota\n\tB\n\tC\n)",
+			want: map[string]string{"A": "0", "B": "1", "C": "2"},
+		},
+		{
+			name: "iota restarts in each block",
+			src:  "const (\n\tA = iota\n\tB\n)\n\nconst (\n\tC = iota\n\tD\n)",
+			want: map[string]string{"A": "0", "B": "1", "C": "0", "D": "1"},
+		},
+		{
+			name:  "shift of iota",
+			src:   "type Flags uint\n\nconst (\n\tRead Flags = 1 << iota\n\tWrite\n\tExec\n)",
+			want:  map[string]string{"Read": "1", "Write": "2", "Exec": "4"},
+			types: map[string]string{"Read": "Flags", "Write": "Flags", "Exec": "Flags"},
+		},
+		{
+			name: "shifted sizes",
+			src:  "const (\n\t_ = iota\n\tKB = 1 << (10 * iota)\n\tMB\n\tGB\n)",
+			want: map[string]string{"KB": "1024", "MB": "1048576", "GB": "1073741824"},
+		},
+		{
+			name: "right shift and arithmetic",
+			src:  "const (\n\tA = 256 >> 4\n\tB = A*2 + 1\n\tC = B % 5\n\tD = 7 / 2\n)",
+			want: map[string]string{"A": "16", "B": "33", "C": "3", "D": "3"},
+		},
+		{
+			name: "complement of sized unsigned conversions",
+			src:  "const (\n\tU8 = ^uint8(0)\n\tU16 = ^uint16(0)\n\tU32 = ^uint32(0)\n\tU64 = ^uint64(0)\n\tB = ^byte(0)\n)",
+			want: map[string]string{
+				"U8":  "255",
+				"U16": "65535",
+				"U32": "4294967295",
+				"U64": "18446744073709551615",
+				"B":   "255",
+			},
+		},
+		{
+			name:  "complement of word-sized unsigned",
+			src:   "const (\n\tMaxUint = ^uint(0)\n\tMaxInt = int(MaxUint >> 1)\n)",
+			sizes: amd64,
+			want:  map[string]string{"MaxUint": "18446744073709551615", "MaxInt": "9223372036854775807"},
+		},
+		{
+			name: "complement of word-sized unsigned without sizes",
+			src:  "const MaxUint = ^uint(0)",
+			want: map[string]string{"MaxUint": ""},
+		},
+		{
+			name: "complement of package type",
+			src:  "type Mask uint16\n\nconst All = ^Mask(0)",
+			want: map[string]string{"All": "65535"},
+		},
+		{
+			name: "complement of typed constant",
+			src:  "const (\n\tZero uint32 = 0\n\tAll = ^Zero\n)",
+			want: map[string]string{"Zero": "0", "All": "4294967295"},
+		},
+		{
+			name: "complement of signed and untyped",
+			src:  "const (\n\tA = ^0\n\tB = ^int8(0)\n\tC = -(1 << 3)\n)",
+			want: map[string]string{"A": "-1", "B": "-1", "C": "-8"},
+		},
+		{
+			name: "complement of other package type",
+			src:  "import \"time\"\n\nconst D = ^time.Duration(0)",
+			want: map[string]string{"D": ""},
+		},
+		{
+			name: "skipped blank entries",
+			src:  "const (\n\t_ = iota\n\tA\n\t_\n\tB\n)",
+			want: map[string]string{"A": "1", "B": "3"},
+		},
+		{
+			name:  "implicit repetition",
+			src:   "type Kind int\n\nconst (\n\tX, Y Kind = iota, iota * 10\n\tZ, W\n)",
+			want:  map[string]string{"X": "0", "Y": "0", "Z": "1", "W": "10"},
+			types: map[string]string{"Z": "Kind", "W": "Kind"},
+		},
+		{
+			name: "forward reference",
+			src:  "const (\n\tA = B + 1\n\tB = 41\n)",
+			want: map[string]string{"A": "42", "B": "41"},
+		},
+		{
+			name: "strings and booleans",
+			src:  "const (\n\tS = \"a\" + \"b\"\n\tT = len(S) > 1\n\tU = 1 < 2\n)",
+			want: map[string]string{"S": `"ab"`, "T": "", "U": "true"},
+		},
+		{
+			name: "conversion keeps the value",
+			src:  "type Level int\n\nconst Debug = Level(-4)",
+			want: map[string]string{"Debug": "-4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := parser.ParseFile(token.NewFileSet(), "p.go", "package p\n\n"+tt.src+"\n", 0)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+
+			values := evaluateConstants([]*ast.File{file}, tt.sizes)
+
+			got := make(map[string]string, len(values))
+			for name, v := range values {
+				got[name] = ""
+				if v.value != nil {
+					got[name] = constString(v.value)
+				}
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("values = %v, want %v", got, tt.want)
+			}
+
+			for name, want := range tt.types {
+				if typ := values[name].typ; typ != want {
+					t.Errorf("type of %s = %q, want %q", name, typ, want)
+				}
+			}
+		})
+	}
+}
@@ -2,17 +2,34 @@ package analyzer
 
 import (
 	"go/ast"
+	"go/doc"
+	"go/doc/comment"
+	"go/types"
+	"sort"
+	"strconv"
 	"strings"
 )
 
-// extractImports extracts all import paths from a package.
-func extractImports(pkg *ast.Package) []string {
+// extractImports extracts the import paths of a package in sorted order,
+// along with the explicit names some of them are imported under.
+func extractImports(pkg *ast.Package) ([]string, map[string]string) {
 	importSet := make(map[string]bool)
+	var names map[string]string
 
-	for _, file := range pkg.Files {
+	for filename, file := range pkg.Files {
+		if strings.HasSuffix(filename, "_test.go") {
+			continue
+		}
 		for _, imp := range file.Imports {
 			path := strings.Trim(imp.Path.Value, `"`)
 			importSet[path] = true
+
+			if imp.Name != nil && imp.Name.Name != "_" && imp.Name.Name != "." {
+				if names == nil {
+					names = make(map[string]string)
+				}
+				names[path] = imp.Name.Name
+			}
 		}
 	}
 
@@ -20,19 +37,156 @@ func extractImports(pkg *ast.Package) []string {
 	for imp := range importSet {
 		imports = append(imports, imp)
 	}
+	sort.Strings(imports)
+
+	return imports, names
+}
+
+// extractDeprecation reports whether a doc comment has a paragraph starting
+// with "Deprecated:", following the Go convention, and returns its message
+// along with the rest of the doc comment, so that the notice is not shown
+// twice.
+func extractDeprecation(doc string) (string, Deprecation) {
+	paragraphs := strings.Split(strings.ReplaceAll(doc, "\r\n", "\n"), "\n\n")
+	for i, paragraph := range paragraphs {
+		message, ok := strings.CutPrefix(strings.TrimSpace(paragraph), "Deprecated:")
+		if !ok {
+			continue
+		}
+		rest := append(paragraphs[:i:i], paragraphs[i+1:]...)
+		return strings.Join(rest, "\n\n"), Deprecation{
+			Deprecated:         true,
+			DeprecationMessage: strings.Join(strings.Fields(message), " "),
+		}
+	}
+	return doc, Deprecation{}
+}
+
+// extractNotes flattens the marked notes of a package, ordered by marker.
+func extractNotes(notes map[string][]*doc.Note) []NoteInfo {
+	markers := make([]string, 0, len(notes))
+	for marker := range notes {
+		markers = append(markers, marker)
+	}
+	sort.Strings(markers)
+
+	var result []NoteInfo
+	for _, marker := range markers {
+		for _, note := range notes[marker] {
+			result = append(result, NoteInfo{
+				Marker: marker,
+				UID:    note.UID,
+				Body:   cleanDoc(note.Body),
+			})
+		}
+	}
+	return result
+}
+
+// extractTypeParams extracts generic type parameters from a field list.
+func extractTypeParams(fields *ast.FieldList, src *sourcePrinter) []TypeParamInfo {
+	if fields == nil {
+		return nil
+	}
+
+	var params []TypeParamInfo
+	for _, field := range fields.List {
+		constraint := src.expr(field.Type)
+		for _, name := range field.Names {
+			params = append(params, TypeParamInfo{
+				Name:       name.Name,
+				Constraint: constraint,
+			})
+		}
+	}
+
+	return params
+}
+
+// extractTypeSet returns the union terms of a constraint interface such as
+// "~int | ~string", or an empty string for ordinary interfaces.
+func extractTypeSet(it *ast.InterfaceType, src *sourcePrinter) string {
+	if it.Methods == nil {
+		return ""
+	}
+
+	var terms []string
+	for _, field := range it.Methods.List {
+		if len(field.Names) > 0 {
+			continue
+		}
+		switch field.Type.(type) {
+		case *ast.BinaryExpr, *ast.UnaryExpr:
+			terms = append(terms, src.expr(field.Type))
+		}
+	}
+
+	return strings.Join(terms, "; ")
+}
+
+// extractInterfaceMethods extracts the methods declared in an interface, with
+// their signatures and doc comments. Unexported methods are only left in the
+// syntax tree by go/doc when documenting unexported symbols.
+func extractInterfaceMethods(it *ast.InterfaceType, src *sourcePrinter) []InterfaceMethodInfo {
+	if it.Methods == nil {
+		return nil
+	}
+
+	var methods []InterfaceMethodInfo
+	for _, field := range it.Methods.List {
+		ft, ok := field.Type.(*ast.FuncType)
+		if !ok {
+			continue
+		}
+
+		description := field.Doc.Text()
+		if description == "" {
+			description = field.Comment.Text()
+		}
+
+		for _, name := range field.Names {
+			methods = append(methods, InterfaceMethodInfo{
+				Name:        name.Name,
+				Signature:   name.Name + strings.TrimPrefix(src.expr(ft), "func"),
+				Description: cleanDoc(description),
+			})
+		}
+	}
+
+	return methods
+}
+
+// extractEmbeds extracts the interfaces embedded in an interface. Single
+// named terms are reported here, since they cannot be told apart from
+// embedded interfaces without type information.
+func extractEmbeds(it *ast.InterfaceType, src *sourcePrinter) []string {
+	if it.Methods == nil {
+		return nil
+	}
+
+	var embeds []string
+	for _, field := range it.Methods.List {
+		if len(field.Names) > 0 {
+			continue
+		}
+		switch field.Type.(type) {
+		case *ast.Ident, *ast.SelectorExpr, *ast.IndexExpr, *ast.IndexListExpr:
+			embeds = append(embeds, src.expr(field.Type))
+		}
+	}
 
-	return imports
+	return embeds
 }
 
 // extractParameters extracts parameter information from a field list.
-func extractParameters(fields *ast.FieldList) []ParameterInfo {
+func extractParameters(fields *ast.FieldList, src *sourcePrinter) []ParameterInfo {
 	if fields == nil {
 		return nil
 	}
 
 	var params []ParameterInfo
 	for _, field := range fields.List {
-		paramType := typeToString(field.Type)
+		paramType := src.print(field.Type)
 
 		if len(field.Names) == 0 {
 			// Anonymous parameter
@@ -52,24 +206,40 @@ func extractParameters(fields *ast.FieldList) []ParameterInfo {
 	return params
 }
 
-// extractReturns extracts return type information from a field list.
-func extractReturns(fields *ast.FieldList) []ReturnInfo {
+// extractReturns extracts return value information from a field list. Named
+// results produce one entry per name.
+func extractReturns(fields *ast.FieldList, src *sourcePrinter) []ReturnInfo {
 	if fields == nil {
 		return nil
 	}
 
 	returns := make([]ReturnInfo, 0, len(fields.List))
 	for _, field := range fields.List {
-		returns = append(returns, ReturnInfo{
-			Type: typeToString(field.Type),
-		})
+		returnType := src.print(field.Type)
+		description := fieldDescription(field)
+
+		if len(field.Names) == 0 {
+			returns = append(returns, ReturnInfo{
+				Type:        returnType,
+				Description: description,
+			})
+			continue
+		}
+
+		for _, name := range field.Names {
+			returns = append(returns, ReturnInfo{
+				Name:        name.Name,
+				Type:        returnType,
+				Description: description,
+			})
+		}
 	}
 
 	return returns
 }
 
 // extractStructFields extracts field information from a struct type.
-func extractStructFields(structType *ast.StructType) []FieldInfo {
+func extractStructFields(structType *ast.StructType, src *sourcePrinter) []FieldInfo {
 	if structType.Fields == nil {
 		return nil
 	}
@@ -77,76 +247,167 @@ func extractStructFields(structType *ast.StructType) []FieldInfo {
 	var fields []FieldInfo
 
 	for _, field := range structType.Fields.List {
-		fieldType := typeToString(field.Type)
-		var tag string
+		info := FieldInfo{
+			Type:        src.print(field.Type),
+			Description: fieldDescription(field),
+		}
 		if field.Tag != nil {
-			tag = field.Tag.Value
+			info.Tag = field.Tag.Value
+			info.Tags = parseStructTag(field.Tag.Value)
 		}
 
 		if len(field.Names) == 0 {
-			// Embedded field
-			fields = append(fields, FieldInfo{
-				Type: fieldType,
-				Tag:  tag,
-			})
-		} else {
-			for _, name := range field.Names {
-				fields = append(fields, FieldInfo{
-					Name: name.Name,
-					Type: fieldType,
-					Tag:  tag,
-				})
-			}
+			info.Name = embeddedFieldName(field.Type)
+			info.Embedded = true
+			fields = append(fields, info)
+			continue
+		}
+
+		for _, name := range field.Names {
+			info.Name = name.Name
+			fields = append(fields, info)
 		}
 	}
 
 	return fields
 }
 
-// extractExamplesFromDoc extracts code examples from documentation comments.
-func extractExamplesFromDoc(doc string) []string {
-	if doc == "" {
-		return nil
+// fieldDescription returns the doc comment of a field, falling back to its
+// trailing line comment.
+func fieldDescription(field *ast.Field) string {
+	if text := field.Doc.Text(); text != "" {
+		return cleanDoc(text)
 	}
+	return cleanDoc(field.Comment.Text())
+}
 
-	var examples []string
-	lines := strings.Split(doc, "\n")
+// embeddedFieldName returns the implicit name of an embedded field, which is
+// its type name without pointer, package qualifier or type arguments.
+func embeddedFieldName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return embeddedFieldName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.IndexExpr:
+		return embeddedFieldName(t.X)
+	case *ast.IndexListExpr:
+		return embeddedFieldName(t.X)
+	default:
+		return types.ExprString(expr)
+	}
+}
 
-	var inExample bool
-	var currentExample strings.Builder
+// parseStructTag parses a struct tag literal into its key/value pairs,
+// following the conventional format used by reflect.StructTag.
+func parseStructTag(literal string) map[string]string {
+	tag, err := strconv.Unquote(literal)
+	if err != nil {
+		return nil
+	}
 
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
+	pairs := make(map[string]string)
+	for tag != "" {
+		tag = strings.TrimLeft(tag, " ")
 
-		// Check for example start markers
-		if strings.HasPrefix(trimmed, "Example:") ||
-			strings.HasPrefix(trimmed, "Usage:") ||
-			strings.Contains(trimmed, "```go") {
-			inExample = true
-			currentExample.Reset()
-			continue
+		// Scan to the colon; a key is a non-empty run of non-control,
+		// non-space, non-quote characters
+		i := 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
 		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		key := tag[:i]
+		tag = tag[i+1:]
 
-		if inExample {
-			// Check for example end markers
-			if strings.Contains(trimmed, "```") ||
-				(trimmed == "" && currentExample.Len() > 0) {
-				if currentExample.Len() > 0 {
-					examples = append(examples, currentExample.String())
-					currentExample.Reset()
-				}
-				inExample = false
-				continue
+		// Scan the quoted value, honoring escapes
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
 			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		value, err := strconv.Unquote(tag[:i+1])
+		if err != nil {
+			break
+		}
+		// Like reflect.StructTag.Lookup, the first occurrence of a key wins
+		if _, ok := pairs[key]; !ok {
+			pairs[key] = value
+		}
+		tag = tag[i+1:]
+	}
+
+	if len(pairs) == 0 {
+		return nil
+	}
+	return pairs
+}
+
+// analyzeExamples converts testable examples harvested from _test.go files.
+func analyzeExamples(examples []*doc.Example, src *sourcePrinter) []ExampleInfo {
+	var result []ExampleInfo
+	for _, ex := range examples {
+		result = append(result, ExampleInfo{
+			Name:   "Example" + ex.Name,
+			Suffix: ex.Suffix,
+			Code:   src.example(ex),
+			Doc:    cleanDoc(ex.Doc),
+			Output: ex.Output,
+			Tested: true,
+		})
+	}
+	return result
+}
+
+// extractExamplesFromDoc extracts the code blocks of a doc comment that are
+// introduced by a paragraph ending in "Example:" or "Usage:", such as
+//
+//	For example:
+//
+//		x := New()
+func extractExamplesFromDoc(doc string) []ExampleInfo {
+	if doc == "" {
+		return nil
+	}
 
-			// Extract indented code lines
-			if strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t") {
-				cleaned := strings.TrimPrefix(strings.TrimPrefix(line, "    "), "\t")
-				currentExample.WriteString(cleaned)
-				currentExample.WriteString("\n")
+	var p comment.Parser
+	var examples []ExampleInfo
+	var intro bool
+	for _, block := range p.Parse(doc).Content {
+		switch b := block.(type) {
+		case *comment.Paragraph:
+			intro = introducesExample(b.Text)
+		case *comment.Code:
+			if intro {
+				examples = append(examples, ExampleInfo{Code: b.Text, InDoc: true})
 			}
+		default:
+			intro = false
 		}
 	}
 
 	return examples
 }
+
+// introducesExample reports whether a paragraph announces an example, as in
+// "Example:", "For example:" or "Usage:".
+func introducesExample(text []comment.Text) bool {
+	var sb strings.Builder
+	for _, t := range text {
+		if plain, ok := t.(comment.Plain); ok {
+			sb.WriteString(string(plain))
+		}
+	}
+
+	paragraph := strings.ToLower(strings.TrimSpace(sb.String()))
+	return strings.HasSuffix(paragraph, "example:") || strings.HasSuffix(paragraph, "usage:")
+}
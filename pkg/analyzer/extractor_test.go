@@ -0,0 +1,136 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"maps"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestParseStructTag(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  string // unquoted tag
+		want map[string]string
+	}{
+		{name: "single key", tag: `json:"name"`, want: map[string]string{"json": "name"}},
+		{
+			name: "multiple keys",
+			tag:  `json:"name,omitempty" yaml:"name" db:"user_name"`,
+			want: map[string]string{"json": "name,omitempty", "yaml": "name", "db": "user_name"},
+		},
+		{
+			name: "value with spaces",
+			tag:  `validate:"min=1, max=10" help:"the display name"`,
+			want: map[string]string{"validate": "min=1, max=10", "help": "the display name"},
+		},
+		{
+			name: "extra spaces between pairs",
+			tag:  `json:"a"   xml:"b"`,
+			want: map[string]string{"json": "a", "xml": "b"},
+		},
+		{name: "escaped quote", tag: `doc:"say \"hi\""`, want: map[string]string{"doc": `say "hi"`}},
+		{name: "empty value", tag: `json:""`, want: map[string]string{"json": ""}},
+		{name: "dash", tag: `json:"-"`, want: map[string]string{"json": "-"}},
+		{name: "first duplicate wins", tag: `json:"a" json:"b"`, want: map[string]string{"json": "a"}},
+		{name: "stops at malformed pair", tag: `json:"a" bad xml:"b"`, want: map[string]string{"json": "a"}},
+		{name: "missing quotes", tag: `json:name`},
+		{name: "unterminated value", tag: `json:"name`},
+		{name: "not a tag", tag: `just some words`},
+		{name: "empty", tag: ``},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, literal := range []string{"`" + tt.tag + "`", strconv.Quote(tt.tag)} {
+				got := parseStructTag(literal)
+				if !maps.Equal(got, tt.want) {
+					t.Errorf("parseStructTag(%s) = %v, want %v", literal, got, tt.want)
+				}
+
+				// Every parsed key agrees with reflect
+				for key, value := range got {
+					if want, ok := reflect.StructTag(tt.tag).Lookup(key); !ok || value != want {
+						t.Errorf("%s = %q, reflect reports %q (found %t)", key, value, want, ok)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestExtractStructFields(t *testing.T) {
+	src := `package p
+
+import (
+	"io"
+	"sync"
+)
+
+type T struct {
+	// Name is the display name.
+	Name string ` + "`json:\"name\" help:\"the display name\"`" + `
+	X, Y int // coordinates
+	*Node
+	io.Reader
+	*sync.Mutex
+	List[int]
+	*Map[string, int]
+	*pkg.Pair[K, V] ` + "`json:\"pair\"`" + `
+}
+`
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	spec := file.Decls[1].(*ast.GenDecl).Specs[0].(*ast.TypeSpec)
+	fields := extractStructFields(spec.Type.(*ast.StructType), newSourcePrinter(fset, []*ast.File{file}))
+
+	want := []FieldInfo{
+		{
+			Name:        "Name",
+			Type:        "string",
+			Tag:         "`json:\"name\" help:\"the display name\"`",
+			Tags:        map[string]string{"json": "name", "help": "the display name"},
+			Description: "Name is the display name.",
+		},
+		{Name: "X", Type: "int", Description: "coordinates"},
+		{Name: "Y", Type: "int", Description: "coordinates"},
+		{Name: "Node", Type: "*Node", Embedded: true},
+		{Name: "Reader", Type: "io.Reader", Embedded: true},
+		{Name: "Mutex", Type: "*sync.Mutex", Embedded: true},
+		{Name: "List", Type: "List[int]", Embedded: true},
+		{Name: "Map", Type: "*Map[string, int]", Embedded: true},
+		{
+			Name:     "Pair",
+			Type:     "*pkg.Pair[K, V]",
+			Tag:      "`json:\"pair\"`",
+			Tags:     map[string]string{"json": "pair"},
+			Embedded: true,
+		},
+	}
+
+	if len(fields) != len(want) {
+		t.Fatalf("got %d fields %v, want %d", len(fields), fieldNames(fields), len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(fields[i], want[i]) {
+			t.Errorf("field %d = %+v, want %+v", i, fields[i], want[i])
+		}
+	}
+}
+
+// fieldNames returns the names of fields, in order.
+func fieldNames(fields []FieldInfo) []string {
+	var names []string
+	for _, field := range fields {
+		names = append(names, field.Name)
+	}
+	return names
+}
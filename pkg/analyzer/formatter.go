@@ -1,103 +1,103 @@
 package analyzer
 
 import (
-	"fmt"
+	"bytes"
 	"go/ast"
+	"go/doc"
+	"go/doc/comment"
+	"go/printer"
+	"go/token"
+	"sort"
 	"strings"
 )
 
-// fieldListToString converts a field list to a string representation.
-func fieldListToString(fields *ast.FieldList) string {
-	if fields == nil {
-		return ""
+// sourcePrinter prints AST nodes as they appear in the source, including the
+// comments that fall inside them.
+type sourcePrinter struct {
+	fset     *token.FileSet
+	comments []*ast.CommentGroup
+}
+
+// newSourcePrinter creates a printer for the nodes of the parsed files.
+func newSourcePrinter(fset *token.FileSet, files []*ast.File) *sourcePrinter {
+	var comments []*ast.CommentGroup
+	for _, file := range files {
+		comments = append(comments, file.Comments...)
 	}
+	sort.Slice(comments, func(i, j int) bool {
+		return comments[i].Pos() < comments[j].Pos()
+	})
 
-	parts := make([]string, 0, len(fields.List))
-	for _, field := range fields.List {
-		fieldType := typeToString(field.Type)
-		if len(field.Names) == 0 {
-			parts = append(parts, fieldType)
-		} else {
-			for _, name := range field.Names {
-				parts = append(parts, fmt.Sprintf("%s %s", name.Name, fieldType))
-			}
-		}
+	return &sourcePrinter{
+		fset:     fset,
+		comments: comments,
 	}
+}
 
-	return strings.Join(parts, ", ")
+// print returns the gofmt-formatted source of node.
+func (p *sourcePrinter) print(node ast.Node) string {
+	var buf bytes.Buffer
+	cfg := printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+	if err := cfg.Fprint(&buf, p.fset, &printer.CommentedNode{Node: node, Comments: p.comments}); err != nil {
+		return ""
+	}
+	return buf.String()
 }
 
-// typeToString converts an AST expression representing a type to its string representation.
-func typeToString(expr ast.Expr) string {
-	switch t := expr.(type) {
-	case *ast.Ident:
-		return t.Name
-	case *ast.StarExpr:
-		return "*" + typeToString(t.X)
-	case *ast.ArrayType:
-		return "[]" + typeToString(t.Elt)
-	case *ast.MapType:
-		return fmt.Sprintf("map[%s]%s", typeToString(t.Key), typeToString(t.Value))
-	case *ast.SelectorExpr:
-		return fmt.Sprintf("%s.%s", typeToString(t.X), t.Sel.Name)
-	case *ast.InterfaceType:
-		return "interface{}"
-	case *ast.ChanType:
-		switch t.Dir {
-		case ast.SEND:
-			return "chan<- " + typeToString(t.Value)
-		case ast.RECV:
-			return "<-chan " + typeToString(t.Value)
-		default:
-			return "chan " + typeToString(t.Value)
-		}
-	case *ast.FuncType:
-		return buildFuncTypeString(t)
-	case *ast.Ellipsis:
-		return "..." + typeToString(t.Elt)
-	default:
-		return "unknown"
+// expr returns the gofmt-formatted source of node without comments, laid
+// out as it is written in the source.
+func (p *sourcePrinter) expr(node ast.Node) string {
+	if node == nil {
+		return ""
 	}
+
+	var buf bytes.Buffer
+	cfg := printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+	if err := cfg.Fprint(&buf, p.fset, node); err != nil {
+		return ""
+	}
+	return buf.String()
 }
 
-// buildFuncTypeString builds a string representation of a function type.
-func buildFuncTypeString(ft *ast.FuncType) string {
-	var parts []string
-	parts = append(parts, "func")
+// signature returns the declaration of fn without its doc comment or body.
+func (p *sourcePrinter) signature(fn *ast.FuncDecl) string {
+	decl := *fn
+	decl.Doc = nil
+	decl.Body = nil
+	return p.expr(&decl)
+}
 
-	if ft.Params != nil {
-		params := fieldListToString(ft.Params)
-		parts = append(parts, fmt.Sprintf("(%s)", params))
-	} else {
-		parts = append(parts, "()")
+// example returns the code of a testable example. The body of a function
+// example is unindented and stripped of its braces and output comment;
+// whole-file examples are printed in full.
+func (p *sourcePrinter) example(ex *doc.Example) string {
+	body, ok := ex.Code.(*ast.BlockStmt)
+	if !ok {
+		return p.print(ex.Code)
 	}
 
-	if ft.Results != nil {
-		results := fieldListToString(ft.Results)
-		if len(ft.Results.List) == 1 && len(ft.Results.List[0].Names) == 0 {
-			parts = append(parts, results)
-		} else {
-			parts = append(parts, fmt.Sprintf("(%s)", results))
+	code := p.print(body)
+	code = strings.TrimSpace(code)
+	code = strings.TrimPrefix(code, "{")
+	code = strings.TrimSuffix(code, "}")
+
+	if ex.Output != "" || ex.EmptyOutput {
+		if i := outputCommentIndex(code); i >= 0 {
+			code = code[:i]
 		}
 	}
 
-	return strings.Join(parts, " ")
+	lines := strings.Split(strings.Trim(code, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, "\t")
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
 }
 
-// exprToString converts an AST expression to its string representation.
-func exprToString(expr ast.Expr) string {
-	switch e := expr.(type) {
-	case *ast.BasicLit:
-		return e.Value
-	case *ast.Ident:
-		return e.Name
-	case *ast.SelectorExpr:
-		return fmt.Sprintf("%s.%s", exprToString(e.X), e.Sel.Name)
-	case *ast.CallExpr:
-		return exprToString(e.Fun) + "(...)"
-	default:
-		return "..."
-	}
+// outputCommentIndex returns the position of the trailing output comment of
+// an example body, or -1 if there is none.
+func outputCommentIndex(code string) int {
+	return max(strings.LastIndex(code, "// Output:"), strings.LastIndex(code, "// Unordered output:"))
 }
 
 // getTypeKind determines the kind of a type expression.
@@ -120,26 +120,23 @@ func getTypeKind(expr ast.Expr) string {
 	}
 }
 
-// cleanDoc cleans and normalizes documentation strings.
-func cleanDoc(doc string) string {
-	if doc == "" {
+// cleanDoc normalizes a doc comment into the canonical gofmt form while
+// keeping its structure: paragraphs, headings, lists and indented code blocks
+// survive, so the text can be parsed again with go/doc/comment when rendered.
+func cleanDoc(text string) string {
+	if strings.TrimSpace(text) == "" {
 		return ""
 	}
 
-	// Normalize line endings and trim whitespace
-	doc = strings.TrimSpace(doc)
-	doc = strings.ReplaceAll(doc, "\r\n", "\n")
-
-	// Split into lines and remove empty lines
-	lines := strings.Split(doc, "\n")
-	cleaned := make([]string, 0, len(lines))
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" {
-			cleaned = append(cleaned, line)
-		}
+	var p comment.Parser
+	var pr comment.Printer
+	lines := strings.Split(string(pr.Comment(p.Parse(text))), "\n")
+	for i, line := range lines {
+		// Comment prints "//" for blank lines, "// " before text and
+		// "//\t" before code
+		line = strings.TrimPrefix(line, "//")
+		lines[i] = strings.TrimPrefix(line, " ")
 	}
 
-	return strings.Join(cleaned, "\n")
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
 }
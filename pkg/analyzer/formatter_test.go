@@ -0,0 +1,144 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func TestSourcePrinterPrint(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  string
+		want string
+	}{
+		{
+			name: "identifier",
+			typ:  "int",
+			want: "int",
+		},
+		{
+			name: "qualified pointer",
+			typ:  "*bytes.Buffer",
+			want: "*bytes.Buffer",
+		},
+		{
+			name: "generic instance",
+			typ:  "map[string][]List[int]",
+			want: "map[string][]List[int]",
+		},
+		{
+			name: "function",
+			typ:  "func(ctx context.Context, n int) (string, error)",
+			want: "func(ctx context.Context, n int) (string, error)",
+		},
+		{
+			name: "empty struct",
+			typ:  "struct{}",
+			want: "struct{}",
+		},
+		{
+			name: "inline struct keeps tags and comments",
+			typ: "struct {\n" +
+				"\t// Name is the display name.\n" +
+				"\tName string `json:\"name\"`\n" +
+				"\tPort int `json:\"port\"` // listening port\n" +
+				"}",
+			want: "struct {\n" +
+				"\t// Name is the display name.\n" +
+				"\tName string `json:\"name\"`\n" +
+				"\tPort int    `json:\"port\"` // listening port\n" +
+				"}",
+		},
+		{
+			name: "array",
+			typ:  "[4]byte",
+			want: "[4]byte",
+		},
+		{
+			name: "array with constant length",
+			typ:  "[2*Size + 1]*T",
+			want: "[2*Size + 1]*T",
+		},
+		{
+			name: "parenthesized",
+			typ:  "[](*int)",
+			want: "[](*int)",
+		},
+		{
+			name: "parenthesized function result",
+			typ:  "func() (func(int) error)",
+			want: "func() func(int) error",
+		},
+		{
+			name: "type set",
+			typ:  "interface{ ~int | ~[]byte }",
+			want: "interface{ ~int | ~[]byte }",
+		},
+		{
+			name: "inline interface",
+			typ:  "interface {\n\tRead(p []byte) (int, error) // reads\n}",
+			want: "interface {\n\tRead(p []byte) (int, error) // reads\n}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, "p.go", "package p\n\ntype T "+tt.typ+"\n", parser.ParseComments)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+
+			spec := file.Decls[0].(*ast.GenDecl).Specs[0].(*ast.TypeSpec)
+			src := newSourcePrinter(fset, []*ast.File{file})
+			if got := src.print(spec.Type); got != tt.want {
+				t.Errorf("print() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSourcePrinterExpr(t *testing.T) {
+	tests := []struct {
+		name      string
+		decl      string
+		wantType  string
+		wantValue string
+	}{
+		{name: "typed array", decl: "var V [4]byte", wantType: "[4]byte"},
+		{name: "parenthesized type", decl: "var V (*int)", wantType: "(*int)"},
+		{name: "generic type", decl: "var V = List[map[string]int]{}", wantValue: "List[map[string]int]{}"},
+		{name: "shift", decl: "const C = 1 << 10", wantValue: "1 << 10"},
+		{name: "mixed precedence", decl: "const C = 1<<10 | 1<<2", wantValue: "1<<10 | 1<<2"},
+		{name: "parentheses", decl: "const C = (1 + 2) * 3", wantValue: "(1 + 2) * 3"},
+		{name: "complement", decl: "const C uint8 = ^uint8(0) >> 1", wantType: "uint8", wantValue: "^uint8(0) >> 1"},
+		{name: "string concatenation", decl: "const C = \"a\" + `b`", wantValue: "\"a\" + `b`"},
+		{name: "composite literal", decl: "var V = []string{\"a\", \"b\"}", wantValue: "[]string{\"a\", \"b\"}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, "p.go", "package p\n\n"+tt.decl+"\n", parser.ParseComments)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+
+			spec := file.Decls[0].(*ast.GenDecl).Specs[0].(*ast.ValueSpec)
+			src := newSourcePrinter(fset, []*ast.File{file})
+			if got := src.expr(spec.Type); got != tt.wantType {
+				t.Errorf("type = %q, want %q", got, tt.wantType)
+			}
+
+			var value ast.Expr
+			if len(spec.Values) > 0 {
+				value = spec.Values[0]
+			}
+			if got := src.expr(value); got != tt.wantValue {
+				t.Errorf("value = %q, want %q", got, tt.wantValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package analyzer
+
+import (
+	"path"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ImportKind classifies an imported package relative to the project.
+type ImportKind string
+
+// Kinds of imported packages.
+const (
+	ImportStdlib     ImportKind = "stdlib"
+	ImportModule     ImportKind = "module" // a package of the project's modules
+	ImportThirdParty ImportKind = "third-party"
+)
+
+// Graph is the import graph of the packages of a project.
+type Graph struct {
+	Nodes  []GraphNode `json:"nodes"`
+	Edges  []GraphEdge `json:"edges"`
+	Cycles [][]string  `json:"cycles,omitempty"` // import paths of the packages in each import cycle
+}
+
+// GraphNode is a package in the import graph.
+type GraphNode struct {
+	ImportPath string     `json:"import_path"`
+	Label      string     `json:"label"` // import path relative to its module, for module packages
+	Kind       ImportKind `json:"kind"`
+	Internal   bool       `json:"internal,omitempty"` // under an internal/ directory
+	InCycle    bool       `json:"in_cycle,omitempty"`
+}
+
+// GraphEdge is an import of one package by another.
+type GraphEdge struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	InCycle bool   `json:"in_cycle,omitempty"`
+}
+
+// ImportKind classifies the import path imp: packages of the project and of
+// its modules are module packages, paths without a dot in their first
+// element belong to the standard library, and anything else is third-party.
+func (p *Project) ImportKind(imp string) ImportKind {
+	if p.Package(imp) != nil || p.modulePath(imp) != "" {
+		return ImportModule
+	}
+	first, _, _ := strings.Cut(imp, "/")
+	if !strings.Contains(first, ".") {
+		return ImportStdlib
+	}
+	return ImportThirdParty
+}
+
+// modulePath returns the path of the project module containing imp, or an
+// empty string if there is none.
+func (p *Project) modulePath(imp string) string {
+	if p == nil {
+		return ""
+	}
+	for _, pkg := range p.Packages {
+		if pkg.Module == nil {
+			continue
+		}
+		if mod := pkg.Module.Path; imp == mod || strings.HasPrefix(imp, mod+"/") {
+			return mod
+		}
+	}
+	return ""
+}
+
+// NewGraph builds the import graph of the project's packages. Imports of the
+// given external kinds, stdlib or third-party, are included as leaf nodes;
+// other external imports are left out. Packages that import each other
+// directly or indirectly are recorded as cycles.
+func NewGraph(project *Project, external ...ImportKind) *Graph {
+	g := &Graph{}
+	nodes := make(map[string]bool)
+	addNode := func(node GraphNode) {
+		if !nodes[node.ImportPath] {
+			nodes[node.ImportPath] = true
+			g.Nodes = append(g.Nodes, node)
+		}
+	}
+
+	for _, pkg := range project.Packages {
+		if pkg.ImportPath == "" {
+			continue
+		}
+		addNode(project.moduleNode(pkg.ImportPath))
+
+		for _, imp := range pkg.Imports {
+			kind := project.ImportKind(imp)
+			switch {
+			case kind == ImportModule:
+				addNode(project.moduleNode(imp))
+			case slices.Contains(external, kind):
+				addNode(GraphNode{ImportPath: imp, Label: imp, Kind: kind})
+			default:
+				continue
+			}
+			g.Edges = append(g.Edges, GraphEdge{From: pkg.ImportPath, To: imp})
+		}
+	}
+
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		return g.Nodes[i].ImportPath < g.Nodes[j].ImportPath
+	})
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+
+	g.markCycles()
+	return g
+}
+
+// moduleNode returns the node of a module package, labeled with its path
+// relative to the module, or with its package name for the module root.
+func (p *Project) moduleNode(imp string) GraphNode {
+	node := GraphNode{ImportPath: imp, Label: imp, Kind: ImportModule, Internal: isInternal(imp)}
+	if mod := p.modulePath(imp); mod != "" {
+		if rel := strings.TrimPrefix(imp, mod+"/"); rel != imp {
+			node.Label = rel
+		} else if pkg := p.Package(imp); pkg != nil {
+			node.Label = pkg.Name
+		} else {
+			node.Label = path.Base(imp)
+		}
+	}
+	return node
+}
+
+// isInternal reports whether an import path lies under an internal/
+// directory, which restricts the packages allowed to import it.
+func isInternal(imp string) bool {
+	for _, elem := range strings.Split(imp, "/") {
+		if elem == "internal" {
+			return true
+		}
+	}
+	return false
+}
+
+// markCycles finds the import cycles of the graph, the strongly connected
+// components with more than one package or a package importing itself, and
+// marks their nodes and edges.
+func (g *Graph) markCycles() {
+	adjacent := make(map[string][]string)
+	for _, e := range g.Edges {
+		adjacent[e.From] = append(adjacent[e.From], e.To)
+	}
+
+	// Tarjan's algorithm
+	index := make(map[string]int)
+	lowlink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	component := make(map[string]int)
+	var visit func(v string)
+	visit = func(v string) {
+		index[v] = len(index)
+		lowlink[v] = index[v]
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, w := range adjacent[v] {
+			if _, seen := index[w]; !seen {
+				visit(w)
+				lowlink[v] = min(lowlink[v], lowlink[w])
+			} else if onStack[w] {
+				lowlink[v] = min(lowlink[v], index[w])
+			}
+		}
+
+		if lowlink[v] != index[v] {
+			return
+		}
+		var scc []string
+		for {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[w] = false
+			scc = append(scc, w)
+			if w == v {
+				break
+			}
+		}
+		if len(scc) > 1 || slices.Contains(adjacent[v], v) {
+			sort.Strings(scc)
+			for _, w := range scc {
+				component[w] = len(g.Cycles) + 1
+			}
+			g.Cycles = append(g.Cycles, scc)
+		}
+	}
+	for _, node := range g.Nodes {
+		if _, seen := index[node.ImportPath]; !seen {
+			visit(node.ImportPath)
+		}
+	}
+
+	for i := range g.Nodes {
+		g.Nodes[i].InCycle = component[g.Nodes[i].ImportPath] != 0
+	}
+	for i := range g.Edges {
+		e := &g.Edges[i]
+		e.InCycle = component[e.From] != 0 && component[e.From] == component[e.To]
+	}
+	sort.Slice(g.Cycles, func(i, j int) bool {
+		return g.Cycles[i][0] < g.Cycles[j][0]
+	})
+}
+
+// Imports returns the edges from the package with the given import path to
+// the packages it imports.
+func (g *Graph) Imports(importPath string) []GraphEdge {
+	var result []GraphEdge
+	for _, e := range g.Edges {
+		if e.From == importPath {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// Node returns the node with the given import path, or nil if there is none.
+func (g *Graph) Node(importPath string) *GraphNode {
+	for i := range g.Nodes {
+		if g.Nodes[i].ImportPath == importPath {
+			return &g.Nodes[i]
+		}
+	}
+	return nil
+}
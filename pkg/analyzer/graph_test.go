@@ -0,0 +1,170 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// analyzeCycle analyzes the packages of the module in testdata/cycle, where
+// a, b and internal/c import each other in a cycle.
+func analyzeCycle(t *testing.T) *Project {
+	t.Helper()
+	a := New()
+	project := NewProject()
+	for _, dir := range []string{".", "a", "b", "internal/c"} {
+		pkg, err := a.AnalyzePackage(filepath.Join("testdata", "cycle", filepath.FromSlash(dir)))
+		if err != nil {
+			t.Fatalf("AnalyzePackage(%s): %v", dir, err)
+		}
+		project.Add(pkg)
+	}
+	return project
+}
+
+func TestNewGraph(t *testing.T) {
+	project := analyzeCycle(t)
+
+	const (
+		root = "example.com/cycle"
+		a    = root + "/a"
+		b    = root + "/b"
+		c    = root + "/internal/c"
+	)
+
+	tests := []struct {
+		name     string
+		external []ImportKind
+		nodes    []GraphNode
+		edges    []GraphEdge
+	}{
+		{
+			name: "module packages",
+			nodes: []GraphNode{
+				{ImportPath: root, Label: "cycle", Kind: ImportModule},
+				{ImportPath: a, Label: "a", Kind: ImportModule, InCycle: true},
+				{ImportPath: b, Label: "b", Kind: ImportModule, InCycle: true},
+				{ImportPath: c, Label: "internal/c", Kind: ImportModule, Internal: true, InCycle: true},
+			},
+			edges: []GraphEdge{
+				{From: root, To: a},
+				{From: a, To: b, InCycle: true},
+				{From: b, To: c, InCycle: true},
+				{From: c, To: a, InCycle: true},
+			},
+		},
+		{
+			name:     "with external packages",
+			external: []ImportKind{ImportStdlib, ImportThirdParty},
+			nodes: []GraphNode{
+				{ImportPath: root, Label: "cycle", Kind: ImportModule},
+				{ImportPath: a, Label: "a", Kind: ImportModule, InCycle: true},
+				{ImportPath: b, Label: "b", Kind: ImportModule, InCycle: true},
+				{ImportPath: c, Label: "internal/c", Kind: ImportModule, Internal: true, InCycle: true},
+				{ImportPath: "fmt", Label: "fmt", Kind: ImportStdlib},
+				{ImportPath: "github.com/acme/log", Label: "github.com/acme/log", Kind: ImportThirdParty},
+				{ImportPath: "strings", Label: "strings", Kind: ImportStdlib},
+			},
+			edges: []GraphEdge{
+				{From: root, To: a},
+				{From: root, To: "fmt"},
+				{From: a, To: b, InCycle: true},
+				{From: a, To: "strings"},
+				{From: b, To: c, InCycle: true},
+				{From: c, To: a, InCycle: true},
+				{From: c, To: "github.com/acme/log"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGraph(project, tt.external...)
+			if !reflect.DeepEqual(g.Nodes, tt.nodes) {
+				t.Errorf("nodes = %+v, want %+v", g.Nodes, tt.nodes)
+			}
+			if !reflect.DeepEqual(g.Edges, tt.edges) {
+				t.Errorf("edges = %+v, want %+v", g.Edges, tt.edges)
+			}
+			if want := [][]string{{a, b, c}}; !reflect.DeepEqual(g.Cycles, want) {
+				t.Errorf("cycles = %v, want %v", g.Cycles, want)
+			}
+		})
+	}
+}
+
+func TestMarkCycles(t *testing.T) {
+	tests := []struct {
+		name   string
+		edges  [][2]string
+		cycles [][]string
+		marked [][2]string // edges in a cycle
+	}{
+		{
+			name:  "acyclic",
+			edges: [][2]string{{"a", "b"}, {"a", "c"}, {"b", "c"}},
+		},
+		{
+			name:   "self import",
+			edges:  [][2]string{{"a", "a"}, {"a", "b"}},
+			cycles: [][]string{{"a"}},
+			marked: [][2]string{{"a", "a"}},
+		},
+		{
+			name:   "two cycles joined by an edge",
+			edges:  [][2]string{{"a", "b"}, {"b", "a"}, {"b", "c"}, {"c", "d"}, {"d", "c"}},
+			cycles: [][]string{{"a", "b"}, {"c", "d"}},
+			marked: [][2]string{{"a", "b"}, {"b", "a"}, {"c", "d"}, {"d", "c"}},
+		},
+		{
+			name:   "nested loops",
+			edges:  [][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}, {"c", "b"}, {"c", "e"}},
+			cycles: [][]string{{"a", "b", "c"}},
+			marked: [][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}, {"c", "b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Graph{}
+			seen := make(map[string]bool)
+			for _, e := range tt.edges {
+				for _, v := range e {
+					if !seen[v] {
+						seen[v] = true
+						g.Nodes = append(g.Nodes, GraphNode{ImportPath: v})
+					}
+				}
+				g.Edges = append(g.Edges, GraphEdge{From: e[0], To: e[1]})
+			}
+
+			g.markCycles()
+
+			if !reflect.DeepEqual(g.Cycles, tt.cycles) {
+				t.Errorf("cycles = %v, want %v", g.Cycles, tt.cycles)
+			}
+
+			var marked [][2]string
+			for _, e := range g.Edges {
+				if e.InCycle {
+					marked = append(marked, [2]string{e.From, e.To})
+				}
+			}
+			if !reflect.DeepEqual(marked, tt.marked) {
+				t.Errorf("marked edges = %v, want %v", marked, tt.marked)
+			}
+
+			inCycle := make(map[string]bool)
+			for _, cycle := range tt.cycles {
+				for _, v := range cycle {
+					inCycle[v] = true
+				}
+			}
+			for _, node := range g.Nodes {
+				if node.InCycle != inCycle[node.ImportPath] {
+					t.Errorf("node %s in cycle = %t, want %t", node.ImportPath, node.InCycle, inCycle[node.ImportPath])
+				}
+			}
+		})
+	}
+}
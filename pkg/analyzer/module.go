@@ -0,0 +1,164 @@
+package analyzer
+
+import (
+	"fmt"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// findModule returns the module enclosing dir, or nil when dir is not inside
+// a module. Modules are cached by their root directory.
+func (a *Analyzer) findModule(dir string) (*ModuleInfo, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	root := findUp(dir, "go.mod")
+	if root == "" {
+		return nil, nil
+	}
+	if mod, ok := a.modules[root]; ok {
+		return mod, nil
+	}
+
+	gomod := filepath.Join(root, "go.mod")
+	data, err := os.ReadFile(gomod)
+	if err != nil {
+		return nil, err
+	}
+	file, err := modfile.ParseLax(gomod, data, nil)
+	if err != nil {
+		return nil, err
+	}
+	if file.Module == nil {
+		return nil, fmt.Errorf("%s has no module directive", gomod)
+	}
+
+	mod := &ModuleInfo{
+		Path:      file.Module.Mod.Path,
+		Dir:       root,
+		Workspace: findWorkspace(root),
+	}
+	if file.Go != nil {
+		mod.GoVersion = file.Go.Version
+	}
+	mod.Version = moduleVersion(root, mod.Path)
+
+	a.modules[root] = mod
+	return mod, nil
+}
+
+// importPath returns the import path of the package in dir, which lies
+// inside mod.
+func importPath(mod *ModuleInfo, dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(mod.Dir, dir)
+	if err != nil || rel == "." {
+		return mod.Path
+	}
+	return mod.Path + "/" + filepath.ToSlash(rel)
+}
+
+// findUp returns the closest directory at or above dir that contains name,
+// or an empty string if there is none.
+func findUp(dir, name string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// findWorkspace returns the go.work file that uses the module rooted at
+// root, or an empty string when the module is not part of a workspace.
+// Like the go command, it honors GOWORK=off and an explicit GOWORK path.
+func findWorkspace(root string) string {
+	gowork := os.Getenv("GOWORK")
+	switch gowork {
+	case "off":
+		return ""
+	case "":
+		dir := findUp(root, "go.work")
+		if dir == "" {
+			return ""
+		}
+		gowork = filepath.Join(dir, "go.work")
+	}
+
+	data, err := os.ReadFile(gowork)
+	if err != nil {
+		return ""
+	}
+	file, err := modfile.ParseWork(gowork, data, nil)
+	if err != nil {
+		return ""
+	}
+
+	for _, use := range file.Use {
+		dir := use.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(filepath.Dir(gowork), dir)
+		}
+		if filepath.Clean(dir) == root {
+			return gowork
+		}
+	}
+	return ""
+}
+
+// moduleVersion returns the highest semantic version tagged for the module in
+// its git repository, or an empty string when there is none. Modules below
+// the repository root use tags prefixed with their directory, e.g.
+// "sub/v1.2.0", and tags must match the major version of the module path.
+// Releases are preferred over pre-releases.
+func moduleVersion(root, modulePath string) string {
+	// The prefix is relative to the repository root, e.g. "sub/", and
+	// resolved by git itself so that symlinked paths still match
+	out, err := exec.Command("git", "-C", root, "rev-parse", "--show-prefix").Output()
+	if err != nil {
+		return ""
+	}
+	prefix := strings.TrimSpace(string(out))
+
+	tags, err := exec.Command("git", "-C", root, "tag", "--list", prefix+"v*").Output()
+	if err != nil {
+		return ""
+	}
+
+	_, pathMajor, _ := module.SplitPathVersion(modulePath)
+	var best string
+	for _, tag := range strings.Fields(string(tags)) {
+		version := strings.TrimPrefix(tag, prefix)
+		if !semver.IsValid(version) || module.CheckPathMajor(version, pathMajor) != nil {
+			continue
+		}
+		if best == "" || betterVersion(version, best) {
+			best = version
+		}
+	}
+	return best
+}
+
+// betterVersion reports whether v should be preferred over best: releases
+// win over pre-releases, then higher versions win.
+func betterVersion(v, best string) bool {
+	vRelease, bestRelease := semver.Prerelease(v) == "", semver.Prerelease(best) == ""
+	if vRelease != bestRelease {
+		return vRelease
+	}
+	return semver.Compare(v, best) > 0
+}
@@ -0,0 +1,246 @@
+package analyzer
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindModule(t *testing.T) {
+	t.Setenv("GOWORK", "")
+
+	root, err := filepath.Abs(filepath.Join("testdata", "modules"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gowork := filepath.Join(root, "go.work")
+
+	tests := []struct {
+		name       string
+		dir        string // package directory, relative to root
+		path       string
+		modDir     string // relative to root
+		goVersion  string
+		workspace  string
+		importPath string
+	}{
+		{
+			name:       "workspace module",
+			dir:        "app",
+			path:       "example.com/app",
+			modDir:     "app",
+			goVersion:  "1.24",
+			workspace:  gowork,
+			importPath: "example.com/app",
+		},
+		{
+			name:       "package below the module root",
+			dir:        "app/cmd/app",
+			path:       "example.com/app",
+			modDir:     "app",
+			goVersion:  "1.24",
+			workspace:  gowork,
+			importPath: "example.com/app/cmd/app",
+		},
+		{
+			name:       "major version module",
+			dir:        "lib",
+			path:       "example.com/lib/v2",
+			modDir:     "lib",
+			goVersion:  "1.23.4",
+			workspace:  gowork,
+			importPath: "example.com/lib/v2",
+		},
+		{
+			name:       "nested module outside the workspace",
+			dir:        "lib/nested/inner",
+			path:       "example.com/nested",
+			modDir:     "lib/nested",
+			importPath: "example.com/nested/inner",
+		},
+		{
+			name:       "module next to the workspace",
+			dir:        "tool",
+			path:       "example.com/tool",
+			modDir:     "tool",
+			goVersion:  "1.22",
+			importPath: "example.com/tool",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(root, filepath.FromSlash(tt.dir))
+			mod, err := New().findModule(dir)
+			if err != nil {
+				t.Fatalf("findModule: %v", err)
+			}
+			if mod == nil {
+				t.Fatal("findModule found no module")
+			}
+
+			// The version depends on the tags of the enclosing repository
+			want := ModuleInfo{
+				Path:      tt.path,
+				Dir:       filepath.Join(root, filepath.FromSlash(tt.modDir)),
+				GoVersion: tt.goVersion,
+				Version:   mod.Version,
+				Workspace: tt.workspace,
+			}
+			if *mod != want {
+				t.Errorf("findModule = %+v, want %+v", *mod, want)
+			}
+			if got := importPath(mod, dir); got != tt.importPath {
+				t.Errorf("importPath = %q, want %q", got, tt.importPath)
+			}
+		})
+	}
+}
+
+func TestFindModuleCached(t *testing.T) {
+	a := New()
+	app, err := a.findModule(filepath.Join("testdata", "modules", "app"))
+	if err != nil {
+		t.Fatalf("findModule: %v", err)
+	}
+	cmd, err := a.findModule(filepath.Join("testdata", "modules", "app", "cmd", "app"))
+	if err != nil {
+		t.Fatalf("findModule: %v", err)
+	}
+	if app != cmd {
+		t.Errorf("packages of one module got different modules %+v and %+v", app, cmd)
+	}
+}
+
+func TestFindModuleOutsideModule(t *testing.T) {
+	dir := t.TempDir()
+	if findUp(dir, "go.mod") != "" {
+		t.Skipf("temporary directory %s is inside a module", dir)
+	}
+
+	mod, err := New().findModule(dir)
+	if err != nil || mod != nil {
+		t.Errorf("findModule = %+v, %v, want no module", mod, err)
+	}
+}
+
+func TestFindModuleWithoutModuleDirective(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("go 1.24\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := New().findModule(dir); err == nil {
+		t.Error("findModule succeeded for a go.mod without a module directive")
+	}
+}
+
+func TestFindWorkspace(t *testing.T) {
+	root, err := filepath.Abs(filepath.Join("testdata", "modules"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := filepath.Join(root, "app")
+	tool := filepath.Join(root, "tool")
+
+	// A workspace elsewhere that uses tool by its absolute path
+	other := filepath.Join(t.TempDir(), "go.work")
+	if err := os.WriteFile(other, []byte("go 1.24\n\nuse "+tool+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		gowork string
+		root   string
+		want   string
+	}{
+		{name: "enclosing workspace", root: app, want: filepath.Join(root, "go.work")},
+		{name: "module not used by the enclosing workspace", root: tool},
+		{name: "workspace disabled", gowork: "off", root: app},
+		{name: "explicit workspace", gowork: other, root: tool, want: other},
+		{name: "explicit workspace not using the module", gowork: other, root: app},
+		{name: "missing explicit workspace", gowork: filepath.Join(t.TempDir(), "go.work"), root: app},
+		{name: "no workspace", root: t.TempDir()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GOWORK", tt.gowork)
+			if got := findWorkspace(tt.root); got != tt.want {
+				t.Errorf("findWorkspace(%s) = %q, want %q", tt.root, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModuleVersion(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_CONFIG_GLOBAL="+os.DevNull,
+			"GIT_CONFIG_NOSYSTEM=1",
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	git("init", "-q")
+	git("commit", "-q", "--allow-empty", "-m", "initial")
+	for _, tag := range []string{
+		"v1.0.0", "v1.2.0", "v1.3.0-rc.1", "v2.0.0", "v2.1.0", "not-a-version",
+		"sub/v0.1.0", "sub/v0.2.0-beta", "other/v9.0.0",
+		"pre/v0.1.0-alpha", "pre/v0.1.0-beta",
+	} {
+		git("tag", tag)
+	}
+
+	sub := filepath.Join(repo, "sub")
+	empty := filepath.Join(repo, "empty")
+	pre := filepath.Join(repo, "pre")
+	for _, dir := range []string{sub, empty, pre} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A symlink to sub still finds the tags of sub
+	link := filepath.Join(t.TempDir(), "link")
+	if err := os.Symlink(sub, link); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		root       string
+		modulePath string
+		want       string
+	}{
+		{name: "release over pre-release", root: repo, modulePath: "example.com/m", want: "v1.2.0"},
+		{name: "major version suffix", root: repo, modulePath: "example.com/m/v2", want: "v2.1.0"},
+		{name: "no tags for the major version", root: repo, modulePath: "example.com/m/v3"},
+		{name: "subdirectory prefix", root: sub, modulePath: "example.com/m/sub", want: "v0.1.0"},
+		{name: "symlinked subdirectory", root: link, modulePath: "example.com/m/sub", want: "v0.1.0"},
+		{name: "only pre-releases", root: pre, modulePath: "example.com/m/pre", want: "v0.1.0-beta"},
+		{name: "untagged subdirectory", root: empty, modulePath: "example.com/m/empty"},
+		{name: "outside a repository", root: t.TempDir(), modulePath: "example.com/m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := moduleVersion(tt.root, tt.modulePath); got != tt.want {
+				t.Errorf("moduleVersion(%s, %s) = %q, want %q", tt.root, tt.modulePath, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package analyzer
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Project holds the analyzed packages of a project so that references from
+// one package to another can be resolved.
+type Project struct {
+	Packages []*PackageInfo
+
+	byImportPath map[string]*PackageInfo
+}
+
+// NewProject creates an empty project.
+func NewProject() *Project {
+	return &Project{byImportPath: make(map[string]*PackageInfo)}
+}
+
+// Add adds an analyzed package to the project. Packages without an import
+// path are kept but cannot be referenced by other packages.
+func (p *Project) Add(pkg *PackageInfo) {
+	p.Packages = append(p.Packages, pkg)
+	if pkg.ImportPath != "" {
+		p.byImportPath[pkg.ImportPath] = pkg
+	}
+}
+
+// Package returns the project package with the given import path, or nil
+// when the project has no such package.
+func (p *Project) Package(importPath string) *PackageInfo {
+	if p == nil {
+		return nil
+	}
+	return p.byImportPath[importPath]
+}
+
+// LocalImports returns the project packages imported by pkg, in import path
+// order.
+func (p *Project) LocalImports(pkg *PackageInfo) []*PackageInfo {
+	var local []*PackageInfo
+	for _, imp := range pkg.Imports {
+		if target := p.Package(imp); target != nil {
+			local = append(local, target)
+		}
+	}
+	sort.Slice(local, func(i, j int) bool {
+		return local[i].ImportPath < local[j].ImportPath
+	})
+	return local
+}
+
+// ResolveQualifier returns the import path that a package qualifier such as
+// "analyzer" in "analyzer.PackageInfo" refers to in the source of pkg, or an
+// empty string when none of its imports is known by that name. Project
+// packages are matched by their declared name, other packages by the last
+// element of their import path.
+func (p *Project) ResolveQualifier(pkg *PackageInfo, qualifier string) string {
+	for _, imp := range pkg.Imports {
+		if importName(p, pkg, imp) == qualifier {
+			return imp
+		}
+	}
+	return ""
+}
+
+// ImportName returns the name under which the source of pkg refers to the
+// package with import path imp: the name it is imported as, or else the
+// declared name of a project package or the last element of the path.
+func (p *Project) ImportName(pkg *PackageInfo, imp string) string {
+	return importName(p, pkg, imp)
+}
+
+// majorVersion matches the major version suffix of a module path, e.g. "v2".
+var majorVersion = regexp.MustCompile(`^v[0-9]+$`)
+
+// importName returns the name under which pkg refers to the import path imp.
+func importName(p *Project, pkg *PackageInfo, imp string) string {
+	if name, ok := pkg.ImportNames[imp]; ok {
+		return name
+	}
+	if target := p.Package(imp); target != nil {
+		return target.Name
+	}
+
+	// Assume the package name matches the last element of its import path
+	// without major version suffixes such as "/v2" or ".v3"
+	base := path.Base(imp)
+	if majorVersion.MatchString(base) {
+		base = path.Base(path.Dir(imp))
+	}
+	base, _, _ = strings.Cut(base, ".")
+	return base
+}
@@ -0,0 +1,10 @@
+// Package a starts an import cycle through b and internal/c.
+package a
+
+import (
+	"example.com/cycle/b"
+	"strings"
+)
+
+// Value returns the value of b.
+func Value() string { return strings.ToUpper(b.Value()) }
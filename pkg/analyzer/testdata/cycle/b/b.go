@@ -0,0 +1,7 @@
+// Package b continues the import cycle.
+package b
+
+import "example.com/cycle/internal/c"
+
+// Value returns the value of c.
+func Value() string { return c.Value() }
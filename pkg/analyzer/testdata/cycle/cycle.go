@@ -0,0 +1,10 @@
+// Package cycle imports a package of an import cycle.
+package cycle
+
+import (
+	"example.com/cycle/a"
+	"fmt"
+)
+
+// Run prints the value of a.
+func Run() { fmt.Println(a.Value()) }
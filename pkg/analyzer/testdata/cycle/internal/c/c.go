@@ -0,0 +1,13 @@
+// Package c closes the import cycle back to a.
+package c
+
+import (
+	"example.com/cycle/a"
+	"github.com/acme/log"
+)
+
+// Value logs and returns a constant.
+func Value() string {
+	log.Print(a.Value)
+	return "c"
+}
@@ -0,0 +1,2 @@
+// Package app is a module used by the workspace.
+package app
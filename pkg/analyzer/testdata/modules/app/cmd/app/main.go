@@ -0,0 +1,4 @@
+// Command app is a package below the module root.
+package main
+
+func main() {}
@@ -0,0 +1,2 @@
+// Package lib is a major version 2 module used by the workspace.
+package lib
@@ -0,0 +1,2 @@
+// Package inner belongs to a module nested in lib, outside the workspace.
+package inner
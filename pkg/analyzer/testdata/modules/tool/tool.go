@@ -0,0 +1,2 @@
+// Package tool is a module next to the workspace but not used by it.
+package tool
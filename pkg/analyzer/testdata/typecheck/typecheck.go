@@ -0,0 +1,44 @@
+// Package typecheck exercises the information only the type checker finds.
+package typecheck
+
+import (
+	"bytes"
+	"io"
+	"time"
+)
+
+// Timeout is evaluated through another package.
+const Timeout = 2 * time.Second
+
+// Size is evaluated through a builtin.
+const Size = len("four")
+
+// Shape has an area.
+type Shape interface {
+	Area() float64
+}
+
+// Square is a Shape.
+type Square struct {
+	Side float64
+}
+
+// Area returns the area of the square.
+func (s Square) Area() float64 { return s.Side * s.Side }
+
+// String describes the square.
+func (s *Square) String() string { return "square" }
+
+// Buffered embeds a buffer and a square, which both have a String method.
+type Buffered struct {
+	*bytes.Buffer
+	Square
+	Out io.Writer
+}
+
+// Copy copies r to a new buffer.
+func Copy(r io.Reader) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+	_, err := buf.ReadFrom(r)
+	return &buf, err
+}
@@ -0,0 +1,4 @@
+package typecheck
+
+// helper is only visible to the tests.
+func helper() int { return 1 }
@@ -0,0 +1,310 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/types"
+	"golang.org/x/tools/go/packages"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// typeCheckMode is the information loaded for type-checked analysis. The
+// package itself is type-checked from source, while its dependencies are
+// read from the export data of the build instead of being type-checked too.
+const typeCheckMode = packages.NeedName | packages.NeedFiles | packages.NeedTypes |
+	packages.NeedSyntax | packages.NeedTypesInfo
+
+// applyTypeInfo loads the package in dir with the Go type checker and fills in
+// the information that cannot be derived from syntax alone: qualified types,
+// implemented interfaces, evaluated constants and promoted methods. files are
+// the paths of the Go files analyzed, used to find the matching package.
+func (a *Analyzer) applyTypeInfo(info *PackageInfo, dir string, files []string) error {
+	cfg := &packages.Config{
+		Mode:       typeCheckMode,
+		Dir:        dir,
+		Env:        append(os.Environ(), "GOOS="+a.build.GOOS, "GOARCH="+a.build.GOARCH),
+		BuildFlags: []string{"-tags=" + strings.Join(a.build.BuildTags, ",")},
+	}
+
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		return fmt.Errorf("load package: %w", err)
+	}
+	pkg := matchPackage(pkgs, info.Name, files)
+	if pkg == nil || pkg.Types == nil {
+		return fmt.Errorf("no type information for package %s in %q", info.Name, dir)
+	}
+	if len(pkg.Errors) > 0 {
+		return fmt.Errorf("type-check package: %v", pkg.Errors[0])
+	}
+
+	tc := &typeChecked{
+		pkg:        pkg.Types,
+		qualifier:  types.RelativeTo(pkg.Types),
+		interfaces: collectInterfaces(pkg.Types),
+	}
+	tc.apply(info)
+
+	return nil
+}
+
+// matchPackage returns the loaded package with the given name that holds one
+// of files, or nil if there is none. Files are compared by identity, since
+// the loaded paths are absolute and may resolve symbolic links.
+func matchPackage(pkgs []*packages.Package, name string, files []string) *packages.Package {
+	var want []os.FileInfo
+	for _, file := range files {
+		if fi, err := os.Stat(file); err == nil {
+			want = append(want, fi)
+		}
+	}
+
+	for _, pkg := range pkgs {
+		if pkg.Name != name {
+			continue
+		}
+		for _, file := range pkg.GoFiles {
+			fi, err := os.Stat(file)
+			if err != nil {
+				continue
+			}
+			if slices.ContainsFunc(want, func(w os.FileInfo) bool { return os.SameFile(w, fi) }) {
+				return pkg
+			}
+		}
+	}
+	return nil
+}
+
+// typeChecked annotates a PackageInfo from a type-checked package.
+type typeChecked struct {
+	pkg        *types.Package
+	qualifier  types.Qualifier
+	interfaces []*types.TypeName
+}
+
+// apply fills in type-checked information for every symbol in info.
+func (tc *typeChecked) apply(info *PackageInfo) {
+	scope := tc.pkg.Scope()
+
+	for i := range info.Types {
+		typ := &info.Types[i]
+		obj, ok := scope.Lookup(typ.Name).(*types.TypeName)
+		if !ok {
+			continue
+		}
+		tc.applyType(typ, obj)
+	}
+
+	for i := range info.Functions {
+		fn := &info.Functions[i]
+		if sig := tc.lookupFunc(fn); sig != nil {
+			tc.applySignature(fn, sig)
+		}
+	}
+
+	for i := range info.Constants {
+		c := &info.Constants[i]
+		obj, ok := scope.Lookup(c.Name).(*types.Const)
+		if !ok {
+			continue
+		}
+		if c.Type == "" {
+			c.Type = tc.displayString(obj.Type())
+		}
+		c.Evaluated = constString(obj.Val())
+	}
+
+	for i := range info.Variables {
+		v := &info.Variables[i]
+		if obj, ok := scope.Lookup(v.Name).(*types.Var); ok {
+			v.QualifiedType = tc.typeString(obj.Type())
+			if v.Type == "" {
+				v.Type = tc.displayString(obj.Type())
+			}
+		}
+	}
+}
+
+// applyType fills in field types, implemented interfaces and promoted methods.
+func (tc *typeChecked) applyType(typ *TypeInfo, obj *types.TypeName) {
+	if st, ok := obj.Type().Underlying().(*types.Struct); ok {
+		for i := 0; i < st.NumFields(); i++ {
+			if field := findField(typ, st.Field(i).Name()); field != nil {
+				field.QualifiedType = tc.typeString(st.Field(i).Type())
+			}
+		}
+	}
+
+	typ.Implements = tc.implements(obj)
+	typ.PromotedMethods = tc.promotedMethods(obj)
+	tc.applyPromoted(typ, obj)
+}
+
+// lookupFunc returns the signature of a function or method in the package.
+func (tc *typeChecked) lookupFunc(fn *FunctionInfo) *types.Signature {
+	if !fn.IsMethod {
+		obj, ok := tc.pkg.Scope().Lookup(fn.Name).(*types.Func)
+		if !ok {
+			return nil
+		}
+		return obj.Type().(*types.Signature)
+	}
+
+	recv, ok := tc.pkg.Scope().Lookup(fn.Receiver).(*types.TypeName)
+	if !ok {
+		return nil
+	}
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(recv.Type()), true, tc.pkg, fn.Name)
+	if method, ok := obj.(*types.Func); ok {
+		return method.Type().(*types.Signature)
+	}
+	return nil
+}
+
+// applySignature records the qualified parameter and result types of fn.
+func (tc *typeChecked) applySignature(fn *FunctionInfo, sig *types.Signature) {
+	if sig.Params().Len() == len(fn.Parameters) {
+		for i := range fn.Parameters {
+			t := sig.Params().At(i).Type()
+			// The final parameter of a variadic function is a slice
+			if sig.Variadic() && i == len(fn.Parameters)-1 {
+				fn.Parameters[i].QualifiedType = "..." + tc.typeString(t.(*types.Slice).Elem())
+				continue
+			}
+			fn.Parameters[i].QualifiedType = tc.typeString(t)
+		}
+	}
+
+	if sig.Results().Len() == len(fn.Returns) {
+		for i := range fn.Returns {
+			fn.Returns[i].QualifiedType = tc.typeString(sig.Results().At(i).Type())
+		}
+	}
+}
+
+// implements returns the interfaces from the package and its imports that
+// the type or a pointer to it implements, written as in the package's source.
+func (tc *typeChecked) implements(obj *types.TypeName) []string {
+	if types.IsInterface(obj.Type()) {
+		return nil
+	}
+
+	var result []string
+	for _, iface := range tc.interfaces {
+		if iface == obj {
+			continue
+		}
+		it, ok := iface.Type().Underlying().(*types.Interface)
+		if !ok || it.NumMethods() == 0 || !it.IsMethodSet() {
+			continue
+		}
+		if types.Implements(obj.Type(), it) || types.Implements(types.NewPointer(obj.Type()), it) {
+			result = append(result, tc.displayString(iface.Type()))
+		}
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+// promotedMethods returns the methods promoted to the type from its embedded
+// fields.
+func (tc *typeChecked) promotedMethods(obj *types.TypeName) []PromotedMethodInfo {
+	st, ok := obj.Type().Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+
+	var result []PromotedMethodInfo
+	mset := types.NewMethodSet(types.NewPointer(obj.Type()))
+	for i := 0; i < mset.Len(); i++ {
+		sel := mset.At(i)
+		if len(sel.Index()) < 2 || !sel.Obj().Exported() {
+			continue
+		}
+		result = append(result, PromotedMethodInfo{
+			Name:      sel.Obj().Name(),
+			Signature: tc.displayString(sel.Type()),
+			From:      tc.displayString(st.Field(sel.Index()[0]).Type()),
+		})
+	}
+
+	return result
+}
+
+// applyPromoted records on each embedded field the methods promoted
+// through it.
+func (tc *typeChecked) applyPromoted(typ *TypeInfo, obj *types.TypeName) {
+	st, ok := obj.Type().Underlying().(*types.Struct)
+	if !ok {
+		return
+	}
+
+	mset := types.NewMethodSet(types.NewPointer(obj.Type()))
+	for i := 0; i < mset.Len(); i++ {
+		sel := mset.At(i)
+		if len(sel.Index()) < 2 || !sel.Obj().Exported() {
+			continue
+		}
+		if field := findField(typ, st.Field(sel.Index()[0]).Name()); field != nil {
+			field.Promoted = append(field.Promoted, sel.Obj().Name())
+		}
+	}
+}
+
+// findField returns the field of typ with the given name, or nil if it was
+// not documented.
+func findField(typ *TypeInfo, name string) *FieldInfo {
+	for i := range typ.Fields {
+		if typ.Fields[i].Name == name {
+			return &typ.Fields[i]
+		}
+	}
+	return nil
+}
+
+// typeString renders t with types from other packages qualified by their
+// import path.
+func (tc *typeChecked) typeString(t types.Type) string {
+	return types.TypeString(t, tc.qualifier)
+}
+
+// displayString renders t the way it would be written in the package's
+// source, with types from other packages qualified by package name.
+func (tc *typeChecked) displayString(t types.Type) string {
+	return types.TypeString(t, func(other *types.Package) string {
+		if other == tc.pkg {
+			return ""
+		}
+		return other.Name()
+	})
+}
+
+// collectInterfaces returns the named, non-generic interfaces declared in
+// pkg, its direct imports and the universe scope.
+func collectInterfaces(pkg *types.Package) []*types.TypeName {
+	var result []*types.TypeName
+	add := func(scope *types.Scope, exportedOnly bool) {
+		for _, name := range scope.Names() {
+			obj, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || (exportedOnly && !obj.Exported()) || !types.IsInterface(obj.Type()) {
+				continue
+			}
+			if named, ok := obj.Type().(*types.Named); ok && named.TypeParams().Len() > 0 {
+				continue
+			}
+			result = append(result, obj)
+		}
+	}
+
+	add(types.Universe, false)
+	add(pkg.Scope(), false)
+	for _, imp := range pkg.Imports() {
+		add(imp.Scope(), true)
+	}
+
+	return result
+}
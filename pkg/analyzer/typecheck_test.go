@@ -0,0 +1,138 @@
+package analyzer
+
+import (
+	"golang.org/x/tools/go/packages"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestAnalyzePackageTypeCheck(t *testing.T) {
+	a := New()
+	a.SetTypeCheck(true)
+
+	info, err := a.AnalyzePackage(filepath.Join("testdata", "typecheck"))
+	if err != nil {
+		t.Fatalf("AnalyzePackage: %v", err)
+	}
+
+	t.Run("constants", func(t *testing.T) {
+		want := map[string]string{"Timeout": "2000000000", "Size": "4"}
+		for _, c := range info.Constants {
+			if c.Evaluated != want[c.Name] {
+				t.Errorf("%s evaluated to %q, want %q", c.Name, c.Evaluated, want[c.Name])
+			}
+		}
+	})
+
+	t.Run("qualified types", func(t *testing.T) {
+		fn := findFunction(t, info, "", "Copy")
+		if got := fn.Parameters[0].QualifiedType; got != "io.Reader" {
+			t.Errorf("parameter type = %q, want %q", got, "io.Reader")
+		}
+		if got := fn.Returns[0].QualifiedType; got != "*bytes.Buffer" {
+			t.Errorf("result type = %q, want %q", got, "*bytes.Buffer")
+		}
+
+		field := findField(findType(t, info, "Buffered"), "Out")
+		if field == nil || field.QualifiedType != "io.Writer" {
+			t.Errorf("field Out = %+v, want qualified type io.Writer", field)
+		}
+	})
+
+	t.Run("implements", func(t *testing.T) {
+		if got := findType(t, info, "Square").Implements; !slices.Equal(got, []string{"Shape"}) {
+			t.Errorf("Square implements %q, want [Shape]", got)
+		}
+
+		got := findType(t, info, "Buffered").Implements
+		for _, want := range []string{"Shape", "io.Reader", "io.Writer"} {
+			if !slices.Contains(got, want) {
+				t.Errorf("Buffered implements %q, want %s among them", got, want)
+			}
+		}
+		if got := findType(t, info, "Shape").Implements; got != nil {
+			t.Errorf("interface Shape implements %q, want none", got)
+		}
+	})
+
+	t.Run("promoted methods", func(t *testing.T) {
+		from := make(map[string]string)
+		for _, m := range findType(t, info, "Buffered").PromotedMethods {
+			from[m.Name] = m.From
+		}
+		want := map[string]string{"Area": "Square", "Read": "*bytes.Buffer", "WriteTo": "*bytes.Buffer"}
+		for name, typ := range want {
+			if from[name] != typ {
+				t.Errorf("%s promoted from %q, want %q", name, from[name], typ)
+			}
+		}
+		// Both embedded fields have a String method, so neither is promoted
+		if typ, ok := from["String"]; ok {
+			t.Errorf("ambiguous String promoted from %q", typ)
+		}
+
+		field := findField(findType(t, info, "Buffered"), "Square")
+		if field == nil || !slices.Equal(field.Promoted, []string{"Area"}) {
+			t.Errorf("field Square = %+v, want Area promoted", field)
+		}
+	})
+}
+
+func TestMatchPackage(t *testing.T) {
+	dir := filepath.Join("testdata", "typecheck")
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := []string{filepath.Join(dir, "typecheck.go")}
+
+	pkg := &packages.Package{Name: "typecheck", GoFiles: []string{filepath.Join(abs, "typecheck.go")}}
+	other := &packages.Package{Name: "typecheck", GoFiles: []string{filepath.Join(abs, "missing.go")}}
+	renamed := &packages.Package{Name: "other", GoFiles: pkg.GoFiles}
+
+	tests := []struct {
+		name string
+		pkgs []*packages.Package
+		want *packages.Package
+	}{
+		{name: "single", pkgs: []*packages.Package{pkg}, want: pkg},
+		{name: "not first", pkgs: []*packages.Package{other, renamed, pkg}, want: pkg},
+		{name: "other files", pkgs: []*packages.Package{other}},
+		{name: "other name", pkgs: []*packages.Package{renamed}},
+		{name: "none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchPackage(tt.pkgs, "typecheck", files); got != tt.want {
+				t.Errorf("matchPackage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// findType returns the type of info with the given name.
+func findType(t *testing.T, info *PackageInfo, name string) *TypeInfo {
+	t.Helper()
+	for i := range info.Types {
+		if info.Types[i].Name == name {
+			return &info.Types[i]
+		}
+	}
+	t.Fatalf("type %s not found", name)
+	return nil
+}
+
+// findFunction returns the function, or the method of recv, with the given
+// name.
+func findFunction(t *testing.T, info *PackageInfo, recv, name string) *FunctionInfo {
+	t.Helper()
+	for i := range info.Functions {
+		if fn := &info.Functions[i]; fn.Receiver == recv && fn.Name == name {
+			return fn
+		}
+	}
+	t.Fatalf("function %s not found", name)
+	return nil
+}
@@ -2,7 +2,9 @@ package analyzer
 
 type PackageInfo struct {
 	Name        string         `json:"name"`
-	Path        string         `json:"path"`
+	Path        string         `json:"path"`                  // directory of the package on disk
+	ImportPath  string         `json:"import_path,omitempty"` // empty outside a module
+	Module      *ModuleInfo    `json:"module,omitempty"`
 	Description string         `json:"description"`
 	Functions   []FunctionInfo `json:"functions"`
 	Types       []TypeInfo     `json:"types"`
@@ -10,6 +12,34 @@ type PackageInfo struct {
 	Variables   []VariableInfo `json:"variables"`
 	Examples    []ExampleInfo  `json:"examples"`
 	Imports     []string       `json:"imports"`
+	// ImportNames holds the explicit names of renamed imports, by import path.
+	ImportNames map[string]string `json:"import_names,omitempty"`
+	ConstDecls  []DeclInfo        `json:"const_decls,omitempty"`
+	VarDecls    []DeclInfo        `json:"var_decls,omitempty"`
+	Notes       []NoteInfo        `json:"notes,omitempty"`
+}
+
+// ModuleInfo describes the module enclosing a package, as read from its go.mod.
+type ModuleInfo struct {
+	Path      string `json:"path"`
+	Dir       string `json:"dir"`                  // directory holding go.mod
+	GoVersion string `json:"go_version,omitempty"` // from the go directive
+	Version   string `json:"version,omitempty"`    // latest release tag, e.g. "v1.2.0"
+	Workspace string `json:"workspace,omitempty"`  // go.work file using the module, if any
+}
+
+// Deprecation records a "Deprecated:" paragraph in a doc comment.
+type Deprecation struct {
+	Deprecated         bool   `json:"deprecated,omitempty"`
+	DeprecationMessage string `json:"deprecation_message,omitempty"`
+}
+
+// NoteInfo represents a marked note in the package comments, such as
+// "BUG(who): text" or "TODO(who): text".
+type NoteInfo struct {
+	Marker string `json:"marker"` // e.g. "BUG"
+	UID    string `json:"uid"`    // the "who" in the marker
+	Body   string `json:"body"`
 }
 
 // FunctionInfo represents information about a function or method.
@@ -17,42 +47,110 @@ type FunctionInfo struct {
 	Name        string          `json:"name"`
 	Signature   string          `json:"signature"`
 	Description string          `json:"description"`
+	TypeParams  []TypeParamInfo `json:"type_params,omitempty"`
 	Parameters  []ParameterInfo `json:"parameters"`
 	Returns     []ReturnInfo    `json:"returns"`
-	Examples    []string        `json:"examples"`
+	Examples    []ExampleInfo   `json:"examples"`
 	IsExported  bool            `json:"is_exported"`
 	IsMethod    bool            `json:"is_method"`
 	Receiver    string          `json:"receiver,omitempty"`
+	// AssociatedType is the type this function constructs, as determined by go/doc.
+	AssociatedType string `json:"associated_type,omitempty"`
+	// BuildConstraint is the build constraint of the declaring file, such
+	// as "linux" or "linux && cgo"; it is empty for files that are always built.
+	BuildConstraint string `json:"build_constraint,omitempty"`
+	Deprecation
 }
 
 // TypeInfo represents information about a type declaration.
 type TypeInfo struct {
-	Name        string      `json:"name"`
-	Kind        string      `json:"kind"` // struct, interface, alias, etc.
-	Description string      `json:"description"`
-	Fields      []FieldInfo `json:"fields,omitempty"`
-	Methods     []string    `json:"methods,omitempty"`
-	IsExported  bool        `json:"is_exported"`
+	Name        string          `json:"name"`
+	Kind        string          `json:"kind"` // struct, interface, alias, etc.
+	Description string          `json:"description"`
+	TypeParams  []TypeParamInfo `json:"type_params,omitempty"`
+	Constraint  string          `json:"constraint,omitempty"` // type-set terms, e.g. "~int | ~string"
+	Fields      []FieldInfo     `json:"fields,omitempty"`
+	Methods     []string        `json:"methods,omitempty"`
+	// Funcs, Consts and Vars name the constructors and the constants and
+	// variables of this type that go/doc associates with it.
+	Funcs  []string  `json:"funcs,omitempty"`
+	Enum   *EnumInfo `json:"enum,omitempty"` // set when the constants of the type form an enumeration
+	Consts []string  `json:"consts,omitempty"`
+	Vars   []string  `json:"vars,omitempty"`
+	// InterfaceMethods and Embeds describe the method set of interface kinds.
+	InterfaceMethods []InterfaceMethodInfo `json:"interface_methods,omitempty"`
+	Embeds           []string              `json:"embeds,omitempty"`
+	IsExported       bool                  `json:"is_exported"`
+	Decl             string                `json:"decl,omitempty"` // declaration as written in the source
+	Examples         []ExampleInfo         `json:"examples,omitempty"`
+	// Implements and PromotedMethods are only filled in type-checked mode.
+	Implements      []string             `json:"implements,omitempty"`
+	PromotedMethods []PromotedMethodInfo `json:"promoted_methods,omitempty"`
+	BuildConstraint string               `json:"build_constraint,omitempty"` // of the declaring file
+	Deprecation
 }
 
-// FieldInfo represents information about a struct field.
-type FieldInfo struct {
+// InterfaceMethodInfo represents a method declared in an interface.
+type InterfaceMethodInfo struct {
 	Name        string `json:"name"`
-	Type        string `json:"type"`
-	Tag         string `json:"tag,omitempty"`
+	Signature   string `json:"signature"` // e.g. "Read(p []byte) (n int, err error)"
+	Description string `json:"description"`
+}
+
+// EnumInfo represents the constants of a type that form an enumeration.
+type EnumInfo struct {
+	Members   []EnumMemberInfo `json:"members"`
+	HasString bool             `json:"has_string"` // the type has a String() string method
+}
+
+// EnumMemberInfo represents a single enumeration constant.
+type EnumMemberInfo struct {
+	Name        string `json:"name"`
+	Value       string `json:"value"` // computed value, e.g. "2" for the third iota
 	Description string `json:"description"`
 }
 
+// PromotedMethodInfo represents a method promoted from an embedded field.
+type PromotedMethodInfo struct {
+	Name      string `json:"name"`
+	Signature string `json:"signature"`
+	From      string `json:"from"` // type of the embedded field
+}
+
+// FieldInfo represents information about a struct field.
+type FieldInfo struct {
+	Name        string            `json:"name"` // type name for embedded fields
+	Type        string            `json:"type"`
+	Tag         string            `json:"tag,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"` // parsed tag, e.g. {"json": "name,omitempty"}
+	Description string            `json:"description"`
+	Embedded    bool              `json:"embedded,omitempty"`
+	// Promoted lists the methods promoted through an embedded field, in
+	// type-checked mode.
+	Promoted []string `json:"promoted,omitempty"`
+	// QualifiedType is the type with import paths, in type-checked mode.
+	QualifiedType string `json:"qualified_type,omitempty"`
+}
+
+// TypeParamInfo represents a generic type parameter and its constraint.
+type TypeParamInfo struct {
+	Name       string `json:"name"`
+	Constraint string `json:"constraint"`
+}
+
 // ParameterInfo represents information about a function parameter.
 type ParameterInfo struct {
-	Name string `json:"name"`
-	Type string `json:"type"`
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	QualifiedType string `json:"qualified_type,omitempty"`
 }
 
 // ReturnInfo represents information about a function return value.
 type ReturnInfo struct {
-	Type        string `json:"type"`
-	Description string `json:"description"`
+	Name          string `json:"name,omitempty"` // set for named results
+	Type          string `json:"type"`
+	Description   string `json:"description"`
+	QualifiedType string `json:"qualified_type,omitempty"`
 }
 
 // ConstantInfo represents information about a constant declaration.
@@ -62,19 +160,40 @@ type ConstantInfo struct {
 	Value       string `json:"value"`
 	Description string `json:"description"`
 	IsExported  bool   `json:"is_exported"`
+	// Evaluated is the computed value, with iota expanded.
+	Evaluated       string `json:"evaluated,omitempty"`
+	BuildConstraint string `json:"build_constraint,omitempty"` // of the declaring file
+	Deprecation
 }
 
 // VariableInfo represents information about a variable declaration.
 type VariableInfo struct {
-	Name        string `json:"name"`
-	Type        string `json:"type"`
-	Description string `json:"description"`
-	IsExported  bool   `json:"is_exported"`
+	Name            string `json:"name"`
+	Type            string `json:"type"`
+	Description     string `json:"description"`
+	IsExported      bool   `json:"is_exported"`
+	QualifiedType   string `json:"qualified_type,omitempty"`
+	BuildConstraint string `json:"build_constraint,omitempty"` // of the declaring file
+	Deprecation
+}
+
+// DeclInfo represents a const or var declaration block as written in the source.
+type DeclInfo struct {
+	Names           []string `json:"names"`
+	Source          string   `json:"source"`
+	Description     string   `json:"description"`
+	Type            string   `json:"type,omitempty"`             // associated type, if any
+	BuildConstraint string   `json:"build_constraint,omitempty"` // of the declaring file
+	Deprecation
 }
 
 // ExampleInfo represents information about a code example.
 type ExampleInfo struct {
-	Name string `json:"name"`
-	Code string `json:"code"`
-	Doc  string `json:"doc"`
+	Name   string `json:"name"`
+	Suffix string `json:"suffix,omitempty"` // e.g. "second" for ExampleFoo_second
+	Code   string `json:"code"`
+	Doc    string `json:"doc"`
+	Output string `json:"output,omitempty"` // expected output from the // Output: comment
+	Tested bool   `json:"tested,omitempty"` // harvested from a compiled Example function
+	InDoc  bool   `json:"in_doc,omitempty"` // a code block of the symbol's own doc comment
 }
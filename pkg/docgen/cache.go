@@ -0,0 +1,292 @@
+package docgen
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cache is a content-addressed on-disk store for LLM responses. Entries are
+// keyed by the provider, model and temperature, the prompt and a fingerprint
+// of the symbol they describe, so unchanged symbols reuse earlier answers
+// across runs.
+type Cache struct {
+	dir string
+}
+
+// CacheStats summarizes the contents of a cache directory.
+type CacheStats struct {
+	Entries   int       `json:"entries"`
+	SizeBytes int64     `json:"size_bytes"`
+	Oldest    time.Time `json:"oldest"`
+	Newest    time.Time `json:"newest"`
+}
+
+// cacheEntry is the on-disk representation of a cached response.
+type cacheEntry struct {
+	Model    string    `json:"model"`
+	Response string    `json:"response"`
+	Created  time.Time `json:"created"`
+}
+
+// cacheFileExt is the extension used for cache entry files.
+const cacheFileExt = ".json"
+
+// NewCache creates a cache that stores entries under dir.
+func NewCache(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// Dir returns the directory the cache stores entries in.
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Get returns the cached response for key. Reading an entry marks it as
+// recently used so that Prune keeps it.
+func (c *Cache) Get(key string) (string, bool) {
+	path := c.entryPath(key)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return "", false
+	}
+
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+
+	return entry.Response, true
+}
+
+// Put stores a response under key.
+func (c *Cache) Put(key, model, response string) error {
+	path := c.entryPath(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(cacheEntry{
+		Model:    model,
+		Response: response,
+		Created:  time.Now().UTC(),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal cache entry: %w", err)
+	}
+
+	// Write to a temporary file first so concurrent readers never see a partial entry
+	tmp, err := os.CreateTemp(filepath.Dir(path), "tmp-*")
+	if err != nil {
+		return fmt.Errorf("create cache entry: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("store cache entry: %w", err)
+	}
+
+	return nil
+}
+
+// Stats walks the cache directory and summarizes its entries.
+func (c *Cache) Stats() (CacheStats, error) {
+	var stats CacheStats
+
+	err := c.walkEntries(func(path string, info os.FileInfo) error {
+		stats.Entries++
+		stats.SizeBytes += info.Size()
+		if stats.Oldest.IsZero() || info.ModTime().Before(stats.Oldest) {
+			stats.Oldest = info.ModTime()
+		}
+		if info.ModTime().After(stats.Newest) {
+			stats.Newest = info.ModTime()
+		}
+		return nil
+	})
+
+	return stats, err
+}
+
+// Clear removes every entry from the cache along with the shard directories
+// holding them. The whole directory is checked first: if it contains anything
+// the cache did not write, nothing is removed, so that a misconfigured cache
+// directory is never wiped.
+func (c *Cache) Clear() error {
+	shards, err := os.ReadDir(c.dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read cache directory %q: %w", c.dir, err)
+	}
+
+	files := make(map[string][]string, len(shards))
+	for _, shard := range shards {
+		shardDir := filepath.Join(c.dir, shard.Name())
+		if !shard.IsDir() || !isCacheShard(shard.Name()) {
+			return fmt.Errorf("refusing to clear %q: %q is not a cache shard", c.dir, shardDir)
+		}
+
+		entries, err := os.ReadDir(shardDir)
+		if err != nil {
+			return fmt.Errorf("read cache directory %q: %w", shardDir, err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !isCacheFile(shard.Name(), entry.Name()) {
+				return fmt.Errorf("refusing to clear %q: %q is not a cache entry", c.dir, filepath.Join(shardDir, entry.Name()))
+			}
+			files[shardDir] = append(files[shardDir], filepath.Join(shardDir, entry.Name()))
+		}
+	}
+
+	for _, shard := range shards {
+		shardDir := filepath.Join(c.dir, shard.Name())
+		for _, path := range files[shardDir] {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("remove cache entry %q: %w", path, err)
+			}
+		}
+		if err := os.Remove(shardDir); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove cache directory %q: %w", shardDir, err)
+		}
+	}
+
+	if err := os.Remove(c.dir); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove cache directory %q: %w", c.dir, err)
+	}
+	return nil
+}
+
+// Prune removes entries that have not been used within maxAge and returns
+// the number of entries removed.
+func (c *Cache) Prune(maxAge time.Duration) (int, error) {
+	cutoff := time.Now().Add(-maxAge)
+	removed := 0
+
+	err := c.walkEntries(func(path string, info os.FileInfo) error {
+		if info.ModTime().After(cutoff) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("remove cache entry %q: %w", path, err)
+		}
+		removed++
+		return nil
+	})
+
+	return removed, err
+}
+
+// entryPath returns the file path for a cache key, sharded by key prefix.
+func (c *Cache) entryPath(key string) string {
+	return filepath.Join(c.dir, key[:2], key+cacheFileExt)
+}
+
+// isCacheShard reports whether name is a shard directory of the cache, named
+// after the first two hexadecimal digits of the keys it holds.
+func isCacheShard(name string) bool {
+	return len(name) == 2 && isHex(name)
+}
+
+// isCacheFile reports whether name is a file the cache writes in the given
+// shard: an entry, or the temporary file of an interrupted Put.
+func isCacheFile(shard, name string) bool {
+	return strings.HasPrefix(name, "tmp-") || isCacheEntry(shard, name)
+}
+
+// isCacheEntry reports whether name is the file of an entry in the given
+// shard, named after its key.
+func isCacheEntry(shard, name string) bool {
+	key, ok := strings.CutSuffix(name, cacheFileExt)
+	return ok && len(key) == sha256.Size*2 && strings.HasPrefix(key, shard) && isHex(key)
+}
+
+// isHex reports whether s consists of lowercase hexadecimal digits.
+func isHex(s string) bool {
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// walkEntries calls fn for every cache entry file, that is every file named
+// after a key in the shard directory of its prefix. Other files are never
+// visited, so that a misconfigured cache directory is left alone. A missing
+// cache directory is treated as empty.
+func (c *Cache) walkEntries(fn func(path string, info os.FileInfo) error) error {
+	shards, err := os.ReadDir(c.dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read cache directory %q: %w", c.dir, err)
+	}
+
+	for _, shard := range shards {
+		if !shard.IsDir() || !isCacheShard(shard.Name()) {
+			continue
+		}
+		shardDir := filepath.Join(c.dir, shard.Name())
+		entries, err := os.ReadDir(shardDir)
+		if err != nil {
+			return fmt.Errorf("read cache directory %q: %w", shardDir, err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || !isCacheEntry(shard.Name(), entry.Name()) {
+				continue
+			}
+			info, err := entry.Info()
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("stat cache entry: %w", err)
+			}
+			if err := fn(filepath.Join(shardDir, entry.Name()), info); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// cacheKey derives a cache key from the LLM provider, model and temperature,
+// the prompt and a fingerprint of the symbol's signature and documentation.
+func cacheKey(provider, model string, temperature float64, prompt, signature, doc string) string {
+	fingerprint := sha256.Sum256([]byte(signature + "\x00" + doc))
+
+	h := sha256.New()
+	h.Write([]byte(provider))
+	h.Write([]byte{0})
+	h.Write([]byte(model))
+	h.Write([]byte{0})
+	h.Write([]byte(strconv.FormatFloat(temperature, 'g', -1, 64)))
+	h.Write([]byte{0})
+	h.Write([]byte(prompt))
+	h.Write([]byte{0})
+	h.Write(fingerprint[:])
+
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,134 @@
+package docgen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// foreignFiles are files a misconfigured cache directory may hold besides
+// the cache entries.
+var foreignFiles = []string{
+	"package.json",
+	"ab/notes.json",
+	"ab/" + cacheKey("x", "y", 0, "", "", "")[:10] + ".json",
+	"docs/config.json",
+}
+
+// newTestCache creates a cache holding an entry for each of keys, last used
+// an hour ago, in a directory that also holds foreignFiles.
+func newTestCache(t *testing.T, keys ...string) *Cache {
+	t.Helper()
+
+	dir := t.TempDir()
+	cache := NewCache(dir)
+	old := time.Now().Add(-time.Hour)
+
+	for _, key := range keys {
+		if err := cache.Put(key, "model", "response "+key); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+		if err := os.Chtimes(cache.entryPath(key), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range foreignFiles {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return cache
+}
+
+// checkForeignFiles fails the test if any of foreignFiles was removed.
+func checkForeignFiles(t *testing.T, cache *Cache) {
+	t.Helper()
+	for _, name := range foreignFiles {
+		if _, err := os.Stat(filepath.Join(cache.Dir(), filepath.FromSlash(name))); err != nil {
+			t.Errorf("foreign file %s: %v", name, err)
+		}
+	}
+}
+
+func TestCacheStatsCountsOnlyEntries(t *testing.T) {
+	cache := newTestCache(t, cacheKey("p", "m", 0, "a", "", ""), cacheKey("p", "m", 0, "b", "", ""))
+
+	stats, err := cache.Stats()
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats.Entries != 2 {
+		t.Errorf("Entries = %d, want 2", stats.Entries)
+	}
+}
+
+func TestCachePruneKeepsForeignFiles(t *testing.T) {
+	stale := cacheKey("p", "m", 0, "stale", "", "")
+	fresh := cacheKey("p", "m", 0, "fresh", "", "")
+	cache := newTestCache(t, stale, fresh)
+
+	// Reading an entry marks it as used
+	if _, ok := cache.Get(fresh); !ok {
+		t.Fatal("Get: entry not found")
+	}
+
+	removed, err := cache.Prune(time.Minute)
+	if err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("Prune removed %d entries, want 1", removed)
+	}
+	if _, ok := cache.Get(stale); ok {
+		t.Error("stale entry survived Prune")
+	}
+	if _, ok := cache.Get(fresh); !ok {
+		t.Error("fresh entry was pruned")
+	}
+	checkForeignFiles(t, cache)
+}
+
+func TestCacheClear(t *testing.T) {
+	key := cacheKey("p", "m", 0, "a", "", "")
+
+	t.Run("refuses foreign files", func(t *testing.T) {
+		cache := newTestCache(t, key)
+		if err := cache.Clear(); err == nil {
+			t.Fatal("Clear succeeded on a directory with foreign files")
+		}
+		if _, ok := cache.Get(key); !ok {
+			t.Error("Clear removed an entry before refusing")
+		}
+		checkForeignFiles(t, cache)
+	})
+
+	t.Run("removes entries", func(t *testing.T) {
+		cache := NewCache(filepath.Join(t.TempDir(), "cache"))
+		if err := cache.Put(key, "model", "response"); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+		if err := cache.Clear(); err != nil {
+			t.Fatalf("Clear: %v", err)
+		}
+		if _, err := os.Stat(cache.Dir()); !os.IsNotExist(err) {
+			t.Errorf("cache directory still exists: %v", err)
+		}
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		cache := NewCache(filepath.Join(t.TempDir(), "missing"))
+		if err := cache.Clear(); err != nil {
+			t.Errorf("Clear: %v", err)
+		}
+	})
+}
@@ -1,6 +1,9 @@
 package docgen
 
-import "fmt"
+import (
+	"fmt"
+	"github.com/docaura/docaura-cli/pkg/analyzer"
+)
 
 // Config represents configuration for documentation generation.
 type Config struct {
@@ -10,6 +13,24 @@ type Config struct {
 	IncludePrivate   bool   `json:"include_private"`
 	GenerateExamples bool   `json:"generate_examples"`
 	Style            string `json:"style"` // "godoc", "markdown", "html"
+	DisableAI        bool   `json:"disable_ai"`
+
+	// MermaidURL is the URL of the Mermaid ES module that the HTML index
+	// loads to draw the dependency graph. When empty, the HTML index loads
+	// no script and lists the imports of each package instead.
+	MermaidURL string `json:"mermaid_url"`
+
+	// RootPath is the relative path from the generated page to the output
+	// directory, used to link between pages.
+	RootPath string `json:"-"`
+
+	// Site locates the pages of the other packages of the project, used to
+	// link references to their symbols. It may be nil.
+	Site *Site `json:"-"`
+
+	// Graph is the package dependency graph embedded in the index page. It
+	// may be nil.
+	Graph *analyzer.Graph `json:"-"`
 }
 
 // Validate validates the configuration and sets defaults.
@@ -22,6 +43,10 @@ func (c *Config) Validate() error {
 		c.Style = "markdown"
 	}
 
+	if c.RootPath == "" {
+		c.RootPath = "."
+	}
+
 	validStyles := map[string]bool{
 		"godoc":    true,
 		"markdown": true,
@@ -34,3 +59,59 @@ func (c *Config) Validate() error {
 
 	return nil
 }
+
+// LLMConfig represents configuration for the language model used to enhance documentation.
+type LLMConfig struct {
+	Provider    string  `json:"provider"` // "groq", "openai", "ollama", "anthropic", "fake"
+	BaseURL     string  `json:"base_url"`
+	Model       string  `json:"model"`
+	APIKeyEnv   string  `json:"api_key_env"`
+	Temperature float64 `json:"temperature"` // 0 uses the provider default
+
+	Concurrency       int `json:"concurrency"`
+	RequestsPerMinute int `json:"requests_per_minute"` // 0 disables the limit
+	TokensPerMinute   int `json:"tokens_per_minute"`   // 0 disables the limit
+	MaxRetries        int `json:"max_retries"`
+}
+
+// Validate validates the LLM configuration and fills in provider defaults.
+func (c *LLMConfig) Validate() error {
+	if c.Provider == "" {
+		c.Provider = defaultLLMProvider
+	}
+
+	preset, ok := llmPresets[c.Provider]
+	if !ok {
+		return fmt.Errorf("invalid LLM provider %q: must be one of groq, openai, ollama, anthropic, fake", c.Provider)
+	}
+
+	if c.BaseURL == "" {
+		c.BaseURL = preset.baseURL
+	}
+
+	if c.Model == "" {
+		c.Model = preset.model
+	}
+
+	if c.APIKeyEnv == "" {
+		c.APIKeyEnv = preset.apiKeyEnv
+	}
+
+	if c.Temperature < 0 || c.Temperature > 2 {
+		return fmt.Errorf("invalid temperature %v: must be between 0 and 2", c.Temperature)
+	}
+
+	if c.Concurrency <= 0 {
+		c.Concurrency = defaultConcurrency
+	}
+
+	if c.MaxRetries <= 0 {
+		c.MaxRetries = defaultMaxRetries
+	}
+
+	if c.RequestsPerMinute < 0 || c.TokensPerMinute < 0 {
+		return fmt.Errorf("rate limits must not be negative")
+	}
+
+	return nil
+}
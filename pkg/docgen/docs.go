@@ -0,0 +1,244 @@
+package docgen
+
+import (
+	"github.com/docaura/docaura-cli/pkg/analyzer"
+	"go/ast"
+	"go/doc/comment"
+	htmltemplate "html/template"
+	"path"
+	"strings"
+)
+
+// docLinkBaseURL is where links to the doc comments of other packages point.
+const docLinkBaseURL = "https://pkg.go.dev"
+
+// docRenderer renders doc comments with go/doc/comment, resolving [Symbol]
+// links against the symbols of the package being documented and of the
+// other packages of the project.
+type docRenderer struct {
+	pkg      *analyzer.PackageInfo
+	site     *Site
+	rootPath string
+}
+
+// newDocRenderer creates a renderer for the doc comments of a page. A nil
+// page resolves only links to standard library packages.
+func newDocRenderer(page *PageData) *docRenderer {
+	if page == nil {
+		return &docRenderer{}
+	}
+	return &docRenderer{pkg: page.PackageInfo, site: page.Site, rootPath: page.RootPath}
+}
+
+// funcs returns the template functions that render doc comments and type
+// references for style.
+func (r *docRenderer) funcs(style string) map[string]any {
+	switch style {
+	case "html":
+		return map[string]any{"doc": r.html, "highlight": r.highlight, "linkType": r.htmlType}
+	case "godoc":
+		return map[string]any{"doc": r.text}
+	default:
+		return map[string]any{"doc": r.markdown, "linkType": r.markdownType}
+	}
+}
+
+// text renders a doc comment as plain text the way go doc does, with links
+// reduced to their text and paragraphs wrapped to fit an indented block.
+func (r *docRenderer) text(text string) string {
+	printer := &comment.Printer{TextWidth: 76}
+	return strings.TrimRight(string(printer.Text(r.parse(text))), "\n")
+}
+
+// markdown renders a doc comment as markdown. Links to symbols of the package
+// point at the headings of the markdown templates.
+func (r *docRenderer) markdown(text string) string {
+	printer := &comment.Printer{
+		HeadingID: func(*comment.Heading) string { return "" },
+		DocLinkURL: func(link *comment.DocLink) string {
+			return r.linkURL(link, (*docRenderer).markdownAnchor)
+		},
+	}
+	return strings.TrimSpace(string(printer.Markdown(r.parse(text))))
+}
+
+// html renders a doc comment as HTML. Links to symbols of the package point
+// at the anchors of the HTML templates.
+func (r *docRenderer) html(text string) htmltemplate.HTML {
+	printer := &comment.Printer{
+		DocLinkURL: func(link *comment.DocLink) string {
+			return r.linkURL(link, (*docRenderer).htmlAnchor)
+		},
+	}
+	return htmltemplate.HTML(printer.HTML(r.parse(text)))
+}
+
+// parse parses a doc comment, recognizing links to the symbols of the
+// package and to the packages it imports.
+func (r *docRenderer) parse(text string) *comment.Doc {
+	parser := &comment.Parser{
+		LookupSym: func(recv, name string) bool {
+			return r.symbolKind(recv, name) != ""
+		},
+		LookupPackage: r.lookupPackage,
+	}
+	return parser.Parse(text)
+}
+
+// linkURL returns the URL of a doc link, using anchor for symbols of the
+// package itself and of the other packages documented with it. Links to
+// any other package point at pkg.go.dev.
+func (r *docRenderer) linkURL(link *comment.DocLink, anchor func(r *docRenderer, kind, recv, name string) string) string {
+	if link.ImportPath == "" {
+		return anchor(r, r.symbolKind(link.Recv, link.Name), link.Recv, link.Name)
+	}
+
+	if target, page := r.pageOf(link.ImportPath); target != nil {
+		if link.Name == "" {
+			return page
+		}
+		if kind := target.symbolKind(link.Recv, link.Name); kind != "" {
+			return page + anchor(target, kind, link.Recv, link.Name)
+		}
+	}
+	return link.DefaultURL(docLinkBaseURL)
+}
+
+// pageOf returns a renderer for the project package with the given import
+// path along with the URL of its page, or nil when no page documents it.
+func (r *docRenderer) pageOf(importPath string) (*docRenderer, string) {
+	if r.site == nil {
+		return nil, ""
+	}
+	target := r.site.Project.Package(importPath)
+	page, ok := r.site.Pages[importPath]
+	if target == nil || !ok {
+		return nil, ""
+	}
+	return &docRenderer{pkg: target, site: r.site}, path.Join(r.rootPath, page)
+}
+
+// markdownAnchor returns the GitHub-style anchor of a symbol's heading.
+// Constants and variables have no heading of their own, so they link to
+// their type or to the section listing them.
+func (r *docRenderer) markdownAnchor(kind, recv, name string) string {
+	switch kind {
+	case "method":
+		return "#" + anchor(recv+name)
+	case "member":
+		return "#" + anchor(recv)
+	case "const":
+		return "#" + anchor(r.declSection(r.pkg.ConstDecls, name, "Constants"))
+	case "var":
+		return "#" + anchor(r.declSection(r.pkg.VarDecls, name, "Variables"))
+	default:
+		return "#" + anchor(name)
+	}
+}
+
+// htmlAnchor returns the element ID of a symbol in the HTML templates.
+func (r *docRenderer) htmlAnchor(kind, recv, name string) string {
+	switch kind {
+	case "method":
+		return "#method-" + recv + "-" + name
+	case "member":
+		return "#type-" + recv
+	}
+	return "#" + kind + "-" + name
+}
+
+// declSection returns the heading under which the declaration of name is
+// listed: its associated type, or fallback for package-level blocks.
+func (r *docRenderer) declSection(decls []analyzer.DeclInfo, name, fallback string) string {
+	for _, decl := range decls {
+		for _, declName := range decl.Names {
+			if declName == name && decl.Type != "" {
+				return decl.Type
+			}
+		}
+	}
+	return fallback
+}
+
+// symbolKind reports whether recv.name (or name, for an empty recv) is an
+// exported symbol of the package, returning "type", "func", "method",
+// "member" for struct fields and interface methods, "const" or "var", or an
+// empty string when there is no such symbol.
+func (r *docRenderer) symbolKind(recv, name string) string {
+	if r.pkg == nil {
+		return ""
+	}
+
+	for _, fn := range r.pkg.Functions {
+		if fn.IsExported && fn.Name == name && fn.IsMethod == (recv != "") && fn.Receiver == recv {
+			if fn.IsMethod {
+				return "method"
+			}
+			return "func"
+		}
+	}
+	if recv != "" {
+		return r.memberKind(recv, name)
+	}
+
+	for _, typ := range r.pkg.Types {
+		if typ.IsExported && typ.Name == name {
+			return "type"
+		}
+	}
+	for _, c := range r.pkg.Constants {
+		if c.IsExported && c.Name == name {
+			return "const"
+		}
+	}
+	for _, v := range r.pkg.Variables {
+		if v.IsExported && v.Name == name {
+			return "var"
+		}
+	}
+	return ""
+}
+
+// memberKind returns "member" if name is an exported field or interface
+// method of the exported type recv, or an empty string otherwise.
+func (r *docRenderer) memberKind(recv, name string) string {
+	if !ast.IsExported(name) {
+		return ""
+	}
+	for _, typ := range r.pkg.Types {
+		if !typ.IsExported || typ.Name != recv {
+			continue
+		}
+		for _, field := range typ.Fields {
+			if field.Name == name {
+				return "member"
+			}
+		}
+		for _, method := range typ.InterfaceMethods {
+			if method.Name == name {
+				return "member"
+			}
+		}
+	}
+	return ""
+}
+
+// lookupPackage resolves a package name used in a doc link to one of the
+// imports of the package.
+func (r *docRenderer) lookupPackage(name string) (string, bool) {
+	if r.pkg == nil {
+		return "", false
+	}
+
+	importPath := r.project().ResolveQualifier(r.pkg, name)
+	return importPath, importPath != ""
+}
+
+// project returns the project of the page, or nil when the page was rendered
+// on its own.
+func (r *docRenderer) project() *analyzer.Project {
+	if r.site == nil {
+		return nil
+	}
+	return r.site.Project
+}
@@ -6,19 +6,20 @@ import (
 	"github.com/docaura/docaura-cli/pkg/analyzer"
 	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/prompts"
+	"log"
 	"strings"
 )
 
 // enhancePackageDescription generates an enhanced description for a package.
-func (g *Generator) enhancePackageDescription(ctx context.Context, pkg *analyzer.PackageInfo) (string, error) {
+func (g *Generator) enhancePackageDescription(ctx context.Context, pkg *analyzer.PackageInfo) (string, bool, error) {
 	template := prompts.NewPromptTemplate(`
 Analyze this Go package and write a clear, concise description (2-3 sentences):
 
 Package: {{.name}}
 Path: {{.path}}
 
-Functions: {{range .functions}}{{.name}}, {{end}}
-Types: {{range .types}}{{.name}}, {{end}}
+Functions: {{range .functions}}{{.Name}}, {{end}}
+Types: {{range .types}}{{.Name}}, {{end}}
 
 Write a professional description that explains:
 1. What this package does
@@ -30,31 +31,31 @@ Keep it under 200 words and avoid marketing language.`,
 
 	prompt, err := template.Format(map[string]any{
 		"name":      pkg.Name,
-		"path":      pkg.Path,
+		"path":      pkg.ImportPath,
 		"functions": pkg.Functions,
 		"types":     pkg.Types,
 	})
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
-	response, err := g.generateContent(ctx, prompt)
+	response, cached, err := g.generateCached(ctx, prompt, "package "+pkg.Name, pkg.Description)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
-	return strings.TrimSpace(response), nil
+	return strings.TrimSpace(response), cached, nil
 }
 
 // enhanceFunctionDescription generates an enhanced description for a function.
-func (g *Generator) enhanceFunctionDescription(ctx context.Context, fn *analyzer.FunctionInfo) (string, error) {
+func (g *Generator) enhanceFunctionDescription(ctx context.Context, fn *analyzer.FunctionInfo) (string, bool, error) {
 	template := prompts.NewPromptTemplate(`
 Write a clear description for this Go function:
 
 Function: {{.name}}
 Signature: {{.signature}}
-{{if .parameters}}Parameters: {{range .parameters}}{{.name}} {{.type}}, {{end}}{{end}}
-{{if .returns}}Returns: {{range .returns}}{{.type}}, {{end}}{{end}}
+{{if .parameters}}Parameters: {{range .parameters}}{{.Name}} {{.Type}}, {{end}}{{end}}
+{{if .returns}}Returns: {{range .returns}}{{.Type}}, {{end}}{{end}}
 
 Describe what it does, when to use it, and any important behavior.
 Keep it concise (1-2 sentences).`,
@@ -67,55 +68,130 @@ Keep it concise (1-2 sentences).`,
 		"returns":    fn.Returns,
 	})
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
-	response, err := g.generateContent(ctx, prompt)
+	response, cached, err := g.generateCached(ctx, prompt, fn.Signature, fn.Description)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
-	return strings.TrimSpace(response), nil
+	return strings.TrimSpace(response), cached, nil
 }
 
 // enhanceTypeDescription generates an enhanced description for a type.
-func (g *Generator) enhanceTypeDescription(ctx context.Context, typ *analyzer.TypeInfo) (string, error) {
+func (g *Generator) enhanceTypeDescription(ctx context.Context, typ *analyzer.TypeInfo) (string, bool, error) {
 	template := prompts.NewPromptTemplate(`
 Write a clear description for this Go type:
 
 Type: {{.name}} ({{.kind}})
-{{if .fields}}Fields: {{range .fields}}{{.name}} {{.type}}, {{end}}{{end}}
+{{if .fields}}Fields: {{range .fields}}{{.Name}} {{.Type}}, {{end}}{{end}}
 {{if .methods}}Methods: {{range .methods}}{{.}}, {{end}}{{end}}
+{{if .interface_methods}}Interface methods: {{range .interface_methods}}{{.Signature}}, {{end}}{{end}}
+{{if .embeds}}Embeds: {{range .embeds}}{{.}}, {{end}}{{end}}
 
 Describe what it represents and how it's used.
 Keep it concise (1-2 sentences).`,
-		[]string{"name", "kind", "fields", "methods"})
+		[]string{"name", "kind", "fields", "methods", "interface_methods", "embeds"})
 
 	prompt, err := template.Format(map[string]any{
-		"name":    typ.Name,
-		"kind":    typ.Kind,
-		"fields":  typ.Fields,
-		"methods": typ.Methods,
+		"name":              typ.Name,
+		"kind":              typ.Kind,
+		"fields":            typ.Fields,
+		"methods":           typ.Methods,
+		"interface_methods": typ.InterfaceMethods,
+		"embeds":            typ.Embeds,
 	})
 	if err != nil {
-		return "", err
+		return "", false, err
+	}
+
+	response, cached, err := g.generateCached(ctx, prompt, typeSignature(typ), typ.Description)
+	if err != nil {
+		return "", false, err
+	}
+
+	return strings.TrimSpace(response), cached, nil
+}
+
+// generateCached returns a cached response for the prompt and symbol if one
+// exists, and otherwise generates one with the LLM and stores it. Failing to
+// store it is only logged. The boolean result reports whether the response
+// came from the cache.
+func (g *Generator) generateCached(ctx context.Context, prompt, signature, doc string) (string, bool, error) {
+	if g.cache == nil {
+		response, err := g.generateContent(ctx, prompt)
+		return response, false, err
+	}
+
+	key := cacheKey(g.provider, g.model, g.temperature, prompt, signature, doc)
+	if response, ok := g.cache.Get(key); ok {
+		return response, true, nil
 	}
 
 	response, err := g.generateContent(ctx, prompt)
 	if err != nil {
-		return "", err
+		return "", false, err
+	}
+
+	// A response that cannot be cached is still usable
+	if err := g.cache.Put(key, g.model, response); err != nil {
+		log.Printf("Warning: cache response: %v", err)
 	}
 
-	return strings.TrimSpace(response), nil
+	return response, false, nil
+}
+
+// typeSignature builds a stable textual signature for a type, used to
+// fingerprint it for caching.
+func typeSignature(typ *analyzer.TypeInfo) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "type %s%s %s", typ.Name, typeParams(typ.TypeParams), typ.Kind)
+	for _, field := range typ.Fields {
+		fmt.Fprintf(&sb, "\n%s %s %s", field.Name, field.Type, field.Tag)
+	}
+	for _, method := range typ.Methods {
+		fmt.Fprintf(&sb, "\nfunc %s", method)
+	}
+	for _, embed := range typ.Embeds {
+		fmt.Fprintf(&sb, "\n%s", embed)
+	}
+	for _, method := range typ.InterfaceMethods {
+		fmt.Fprintf(&sb, "\n%s", method.Signature)
+	}
+	return sb.String()
 }
 
 // generateContent is a helper method to generate content using the LLM.
 func (g *Generator) generateContent(ctx context.Context, prompt string) (string, error) {
-	response, err := g.llm.GenerateContent(ctx, []llms.MessageContent{
+	var opts []llms.CallOption
+	if g.temperature > 0 {
+		opts = append(opts, llms.WithTemperature(g.temperature))
+	}
+
+	messages := []llms.MessageContent{
 		llms.TextParts(llms.ChatMessageTypeHuman, prompt),
-	})
-	if err != nil {
-		return "", err
+	}
+
+	var response *llms.ContentResponse
+	for attempt := 0; ; attempt++ {
+		if err := g.limiter.wait(ctx, estimateTokens(prompt)); err != nil {
+			return "", err
+		}
+
+		var err error
+		response, err = g.llm.GenerateContent(ctx, messages, opts...)
+		if err == nil {
+			break
+		}
+
+		if attempt >= g.maxRetries || !isRetryable(err) {
+			return "", err
+		}
+
+		if err := sleepContext(ctx, retryDelay(attempt)); err != nil {
+			return "", err
+		}
 	}
 
 	if len(response.Choices) == 0 {
@@ -8,14 +8,14 @@ import (
 )
 
 // generatePackageExample generates a package-level usage example.
-func (g *Generator) generatePackageExample(ctx context.Context, pkg *analyzer.PackageInfo) (string, error) {
+func (g *Generator) generatePackageExample(ctx context.Context, pkg *analyzer.PackageInfo) (string, bool, error) {
 	template := prompts.NewPromptTemplate(`
 Create a realistic Go code example showing how to use this package:
 
 Package: {{.name}}
 Description: {{.description}}
-Key Functions: {{range .functions}}{{if .is_exported}}{{.name}}, {{end}}{{end}}
-Key Types: {{range .types}}{{if .is_exported}}{{.name}}, {{end}}{{end}}
+Key Functions: {{range .functions}}{{if and .IsExported (not .Deprecated)}}{{.Name}}, {{end}}{{end}}
+Key Types: {{range .types}}{{if and .IsExported (not .Deprecated)}}{{.Name}}, {{end}}{{end}}
 
 Write a complete, runnable example that shows:
 1. Import statement
@@ -33,26 +33,26 @@ Return only the Go code, no explanations.`,
 		"types":       pkg.Types,
 	})
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
-	response, err := g.generateContent(ctx, prompt)
+	response, cached, err := g.generateCached(ctx, prompt, "package "+pkg.Name, pkg.Description)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
-	return strings.TrimSpace(response), nil
+	return strings.TrimSpace(response), cached, nil
 }
 
 // generateFunctionExample generates an example for a specific function.
-func (g *Generator) generateFunctionExample(ctx context.Context, fn *analyzer.FunctionInfo, pkg *analyzer.PackageInfo) (string, error) {
+func (g *Generator) generateFunctionExample(ctx context.Context, fn *analyzer.FunctionInfo, pkg *analyzer.PackageInfo) (string, bool, error) {
 	template := prompts.NewPromptTemplate(`
 Create a Go code example for this function:
 
 Function: {{.name}}
 Signature: {{.signature}}
 Package: {{.package}}
-{{if .parameters}}Parameters: {{range .parameters}}{{.name}} {{.type}}, {{end}}{{end}}
+{{if .parameters}}Parameters: {{range .parameters}}{{.Name}} {{.Type}}, {{end}}{{end}}
 
 Write a realistic example showing how to call this function.
 Include proper error handling if needed.
@@ -66,13 +66,13 @@ Return only the Go code snippet.`,
 		"parameters": fn.Parameters,
 	})
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
-	response, err := g.generateContent(ctx, prompt)
+	response, cached, err := g.generateCached(ctx, prompt, fn.Signature, fn.Description)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
-	return strings.TrimSpace(response), nil
+	return strings.TrimSpace(response), cached, nil
 }
@@ -0,0 +1,115 @@
+package docgen
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/tmc/langchaingo/llms"
+	"strings"
+	"sync"
+)
+
+// FakeLLM is a deterministic llms.Model for tests and dry runs. It returns
+// scripted responses in order and, once they are exhausted, answers derived
+// from a hash of the prompt. Every prompt it receives is recorded.
+type FakeLLM struct {
+	mu        sync.Mutex
+	responses []string
+	next      int
+	prompts   []string
+	err       error
+}
+
+// Compile-time check that FakeLLM implements llms.Model.
+var _ llms.Model = (*FakeLLM)(nil)
+
+// NewFakeLLM creates a fake LLM that returns the given responses in order
+// before falling back to hash-derived answers.
+func NewFakeLLM(responses ...string) *FakeLLM {
+	return &FakeLLM{
+		responses: responses,
+	}
+}
+
+// GenerateContent records the prompt and returns the next scripted or
+// hash-derived response.
+func (f *FakeLLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	prompt := messagesToText(messages)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.prompts = append(f.prompts, prompt)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	var content string
+	if f.next < len(f.responses) {
+		content = f.responses[f.next]
+		f.next++
+	} else {
+		content = fakeAnswer(prompt)
+	}
+
+	return &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{Content: content}},
+	}, nil
+}
+
+// Call generates a response for a single text prompt.
+func (f *FakeLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, f, prompt, options...)
+}
+
+// Prompts returns a copy of every prompt received so far, in order.
+func (f *FakeLLM) Prompts() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	prompts := make([]string, len(f.prompts))
+	copy(prompts, f.prompts)
+	return prompts
+}
+
+// Fail makes every following call return err, as a failing provider would.
+// A nil err restores the normal responses.
+func (f *FakeLLM) Fail(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.err = err
+}
+
+// Reset clears the recorded prompts and rewinds the scripted responses.
+func (f *FakeLLM) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.prompts = nil
+	f.next = 0
+}
+
+// messagesToText joins the text parts of a message sequence.
+func messagesToText(messages []llms.MessageContent) string {
+	var parts []string
+	for _, msg := range messages {
+		for _, part := range msg.Parts {
+			if text, ok := part.(llms.TextContent); ok {
+				parts = append(parts, text.Text)
+			}
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// fakeAnswer derives a stable answer from a prompt.
+func fakeAnswer(prompt string) string {
+	sum := sha256.Sum256([]byte(prompt))
+	return fmt.Sprintf("Generated content %s.", hex.EncodeToString(sum[:6]))
+}
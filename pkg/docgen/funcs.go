@@ -0,0 +1,324 @@
+package docgen
+
+import (
+	"github.com/docaura/docaura-cli/pkg/analyzer"
+	"go/ast"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// baseFuncMap returns the helper functions available to every template.
+func baseFuncMap() map[string]any {
+	return map[string]any{
+		"lower":             strings.ToLower,
+		"trimSpace":         strings.TrimSpace,
+		"trimPrefix":        trimPrefix,
+		"indent":            indent,
+		"blockquote":        blockquote,
+		"anchor":            anchor,
+		"codeFence":         codeFence,
+		"firstSentence":     firstSentence,
+		"escapeMarkdown":    escapeMarkdown,
+		"tableCell":         tableCell,
+		"codeCell":          codeCell,
+		"plainFuncs":        plainFuncs,
+		"funcsOf":           funcsOf,
+		"methodsOf":         methodsOf,
+		"exportedDecls":     exportedDecls,
+		"unexportedDecls":   unexportedDecls,
+		"exportedNames":     exportedNames,
+		"declKind":          declKind,
+		"unexportedFuncs":   unexportedFuncs,
+		"unexportedMethods": unexportedMethods,
+		"unexportedTypes":   unexportedTypes,
+		"notesOf":           notesOf,
+		"buildNote":         buildNote,
+		"separateExamples":  separateExamples,
+		"typeParams":        typeParams,
+		"constraintBody":    constraintBody,
+		"dot":               dotGraph,
+		"mermaid":           mermaidGraph,
+	}
+}
+
+// trimPrefix removes prefix from s; the argument order suits template pipelines.
+func trimPrefix(prefix, s string) string {
+	return strings.TrimPrefix(s, prefix)
+}
+
+// indent prefixes every non-empty line of s with n spaces.
+func indent(n int, s string) string {
+	prefix := strings.Repeat(" ", n)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// blockquote quotes markdown text, prefixing each of its lines with ">".
+func blockquote(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("> "+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// typeParams renders a type parameter list such as "[K comparable, V any]",
+// or an empty string for non-generic declarations.
+func typeParams(params []analyzer.TypeParamInfo) string {
+	if len(params) == 0 {
+		return ""
+	}
+
+	parts := make([]string, 0, len(params))
+	for _, param := range params {
+		parts = append(parts, param.Name+" "+param.Constraint)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// constraintBody renders the body of a constraint interface from its
+// type-set terms, or an empty string when there are none.
+func constraintBody(constraint string) string {
+	if constraint == "" {
+		return ""
+	}
+	return " { " + constraint + " }"
+}
+
+// buildNote describes a build constraint for readers: "linux only" for a
+// single tag, or the full expression prefixed with "requires" otherwise.
+func buildNote(expr string) string {
+	if !strings.ContainsAny(expr, " !()&|") {
+		return expr + " only"
+	}
+	return "requires " + expr
+}
+
+// notesOf returns the package notes with the given marker, e.g. "BUG".
+func notesOf(notes []analyzer.NoteInfo, marker string) []analyzer.NoteInfo {
+	var result []analyzer.NoteInfo
+	for _, note := range notes {
+		if note.Marker == marker {
+			result = append(result, note)
+		}
+	}
+	return result
+}
+
+// separateExamples returns the examples that are not code blocks of the
+// symbol's doc comment, which is rendered with its code blocks already.
+func separateExamples(examples []analyzer.ExampleInfo) []analyzer.ExampleInfo {
+	var result []analyzer.ExampleInfo
+	for _, ex := range examples {
+		if !ex.InDoc {
+			result = append(result, ex)
+		}
+	}
+	return result
+}
+
+// plainFuncs returns the exported package-level functions that are neither
+// methods nor associated with a type.
+func plainFuncs(fns []analyzer.FunctionInfo) []analyzer.FunctionInfo {
+	var result []analyzer.FunctionInfo
+	for _, fn := range fns {
+		if fn.IsExported && !fn.IsMethod && fn.AssociatedType == "" {
+			result = append(result, fn)
+		}
+	}
+	return result
+}
+
+// funcsOf returns the exported functions associated with (typically
+// constructing) the named type.
+func funcsOf(fns []analyzer.FunctionInfo, typeName string) []analyzer.FunctionInfo {
+	var result []analyzer.FunctionInfo
+	for _, fn := range fns {
+		if fn.IsExported && !fn.IsMethod && fn.AssociatedType == typeName {
+			result = append(result, fn)
+		}
+	}
+	return result
+}
+
+// methodsOf returns the exported methods of the named type.
+func methodsOf(fns []analyzer.FunctionInfo, typeName string) []analyzer.FunctionInfo {
+	var result []analyzer.FunctionInfo
+	for _, fn := range fns {
+		if fn.IsExported && fn.IsMethod && fn.Receiver == typeName {
+			result = append(result, fn)
+		}
+	}
+	return result
+}
+
+// exportedDecls returns the declaration blocks associated with the named type
+// (or with no type when typeName is empty) that declare an exported name.
+func exportedDecls(decls []analyzer.DeclInfo, typeName string) []analyzer.DeclInfo {
+	var result []analyzer.DeclInfo
+	for _, decl := range decls {
+		if decl.Type != typeName {
+			continue
+		}
+		for _, name := range decl.Names {
+			if ast.IsExported(name) {
+				result = append(result, decl)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// declKind returns the keyword of a declaration block, "const" or "var".
+func declKind(decl analyzer.DeclInfo) string {
+	kind, _, _ := strings.Cut(decl.Source, " ")
+	return kind
+}
+
+// unexportedDecls returns the declaration blocks that declare no exported
+// name, whatever their associated type.
+func unexportedDecls(decls []analyzer.DeclInfo) []analyzer.DeclInfo {
+	var result []analyzer.DeclInfo
+	for _, decl := range decls {
+		if !slices.ContainsFunc(decl.Names, ast.IsExported) {
+			result = append(result, decl)
+		}
+	}
+	return result
+}
+
+// unexportedFuncs returns the unexported package-level functions, including
+// those associated with a type.
+func unexportedFuncs(fns []analyzer.FunctionInfo) []analyzer.FunctionInfo {
+	var result []analyzer.FunctionInfo
+	for _, fn := range fns {
+		if !fn.IsExported && !fn.IsMethod {
+			result = append(result, fn)
+		}
+	}
+	return result
+}
+
+// unexportedMethods returns the unexported methods of every type.
+func unexportedMethods(fns []analyzer.FunctionInfo) []analyzer.FunctionInfo {
+	var result []analyzer.FunctionInfo
+	for _, fn := range fns {
+		if !fn.IsExported && fn.IsMethod {
+			result = append(result, fn)
+		}
+	}
+	return result
+}
+
+// unexportedTypes returns the unexported types.
+func unexportedTypes(types []analyzer.TypeInfo) []analyzer.TypeInfo {
+	var result []analyzer.TypeInfo
+	for _, typ := range types {
+		if !typ.IsExported {
+			result = append(result, typ)
+		}
+	}
+	return result
+}
+
+// anchor converts a heading into a GitHub-style anchor slug.
+func anchor(s string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			sb.WriteRune(r)
+		case unicode.IsSpace(r):
+			sb.WriteRune('-')
+		}
+	}
+	return sb.String()
+}
+
+// backtickRun matches runs of backticks inside code.
+var backtickRun = regexp.MustCompile("`+")
+
+// codeFence wraps code in a markdown fence tagged with lang. The fence is
+// made longer than any backtick run inside the code.
+func codeFence(lang, code string) string {
+	longest := 2
+	for _, run := range backtickRun.FindAllString(code, -1) {
+		longest = max(longest, len(run))
+	}
+	fence := strings.Repeat("`", longest+1)
+	return fence + lang + "\n" + strings.TrimRight(code, "\n") + "\n" + fence
+}
+
+// firstSentence returns the first sentence of a doc comment, with line
+// breaks collapsed into spaces.
+func firstSentence(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	for i := 0; i < len(s); i++ {
+		if s[i] != '.' || (i+1 < len(s) && s[i+1] != ' ') {
+			continue
+		}
+		// Skip initials such as "A. Person"
+		if i >= 2 && s[i-2] == ' ' && unicode.IsUpper(rune(s[i-1])) {
+			continue
+		}
+		return s[:i+1]
+	}
+	return s
+}
+
+// markdownEscaper escapes characters with special meaning in inline markdown.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"[", `\[`,
+	"]", `\]`,
+	"<", `\<`,
+	">", `\>`,
+	"|", `\|`,
+	"#", `\#`,
+)
+
+// escapeMarkdown escapes s for use in markdown text.
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// tableCell escapes s for use in a markdown table cell, joining its lines.
+func tableCell(s string) string {
+	return strings.Join(strings.Fields(escapeMarkdown(s)), " ")
+}
+
+// codeCell formats s as inline code in a markdown table cell. Line breaks
+// are collapsed, since a cell cannot span lines, and pipes are escaped
+// because they end the cell even inside code spans. Code containing
+// backticks, such as struct tags, gets a longer delimiter.
+func codeCell(s string) string {
+	if s == "" {
+		return ""
+	}
+	s = lineBreaks.ReplaceAllString(s, " ")
+	s = strings.ReplaceAll(s, "|", `\|`)
+
+	longest := 0
+	for _, run := range backtickRun.FindAllString(s, -1) {
+		longest = max(longest, len(run))
+	}
+	if longest == 0 {
+		return "`" + s + "`"
+	}
+	delim := strings.Repeat("`", longest+1)
+	return delim + " " + s + " " + delim
+}
+
+// lineBreaks matches line breaks along with the indentation that follows.
+var lineBreaks = regexp.MustCompile(`\n\s*`)
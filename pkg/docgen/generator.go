@@ -5,22 +5,33 @@ import (
 	"fmt"
 	"github.com/docaura/docaura-cli/pkg/analyzer"
 	"github.com/tmc/langchaingo/llms"
-	"github.com/tmc/langchaingo/llms/openai"
-	"os"
+	"go/ast"
+	"slices"
+	"strings"
+	"sync"
 )
 
 // Generator generates documentation from Go package information using AI enhancement.
 type Generator struct {
-	llm       llms.Model
-	templates *TemplateManager
+	llm         llms.Model
+	provider    string
+	model       string
+	temperature float64
+	cache       *Cache
+	limiter     *rateLimiter
+	concurrency int
+	maxRetries  int
+	templates   *TemplateManager
 }
 
-// New creates a new documentation generator instance.
-func New() (*Generator, error) {
-	llm, err := openai.New(
-		openai.WithModel("llama3-8b-8192"),
-		openai.WithToken(os.Getenv("GROQ_API_KEY")),
-		openai.WithBaseURL("https://api.groq.com/openai/v1"))
+// New creates a new documentation generator instance using the LLM provider
+// described by llmConfig.
+func New(llmConfig LLMConfig) (*Generator, error) {
+	if err := llmConfig.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid LLM config: %w", err)
+	}
+
+	llm, err := newLLM(llmConfig)
 	if err != nil {
 		return nil, fmt.Errorf("create LLM client: %w", err)
 	}
@@ -31,7 +42,26 @@ func New() (*Generator, error) {
 	}
 
 	return &Generator{
-		llm:       llm,
+		llm:         llm,
+		provider:    llmConfig.Provider,
+		model:       llmConfig.Model,
+		temperature: llmConfig.Temperature,
+		limiter:     newRateLimiter(llmConfig.RequestsPerMinute, llmConfig.TokensPerMinute),
+		concurrency: llmConfig.Concurrency,
+		maxRetries:  llmConfig.MaxRetries,
+		templates:   templates,
+	}, nil
+}
+
+// NewOffline creates a new documentation generator without an LLM. The
+// resulting generator only renders the documentation found in the source.
+func NewOffline() (*Generator, error) {
+	templates, err := NewTemplateManager()
+	if err != nil {
+		return nil, fmt.Errorf("create template manager: %w", err)
+	}
+
+	return &Generator{
 		templates: templates,
 	}, nil
 }
@@ -44,95 +74,410 @@ func NewWithLLM(llm llms.Model) (*Generator, error) {
 	}
 
 	return &Generator{
-		llm:       llm,
-		templates: templates,
+		llm:         llm,
+		limiter:     newRateLimiter(0, 0),
+		concurrency: defaultConcurrency,
+		maxRetries:  defaultMaxRetries,
+		templates:   templates,
 	}, nil
 }
 
-// GeneratePackageDoc generates documentation for a Go package.
-func (g *Generator) GeneratePackageDoc(ctx context.Context, pkg *analyzer.PackageInfo, config Config) (string, error) {
+// LoadTemplates overrides the built-in templates with the section files
+// found in dir. See TemplateManager.LoadDir for the naming scheme.
+func (g *Generator) LoadTemplates(dir string) error {
+	return g.templates.LoadDir(dir)
+}
+
+// SetCache sets the cache used to reuse LLM responses across runs. A nil
+// cache disables caching.
+func (g *Generator) SetCache(cache *Cache) {
+	g.cache = cache
+}
+
+// GeneratePackageDoc generates documentation for a Go package. The result
+// records the outcome of every LLM enhancement so that failures are visible
+// to the caller instead of silently dropped.
+func (g *Generator) GeneratePackageDoc(ctx context.Context, pkg *analyzer.PackageInfo, config Config) (*Result, error) {
 	if err := config.Validate(); err != nil {
-		return "", fmt.Errorf("invalid config: %w", err)
+		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
-	// Create a copy to avoid modifying the original
-	enhancedPkg := *pkg
-
-	// Enhance descriptions with AI
-	if err := g.enhanceDescriptions(ctx, &enhancedPkg); err != nil {
-		return "", fmt.Errorf("enhance descriptions: %w", err)
+	// Create a copy to avoid modifying the original; unexported symbols are
+	// dropped unless requested, so they are neither rendered nor sent to the LLM
+	enhancedPkg := clonePackage(pkg)
+	if !config.IncludePrivate {
+		enhancedPkg = exportedOnly(enhancedPkg)
 	}
+	result := &Result{}
 
-	// Generate usage examples if requested
-	if config.GenerateExamples {
-		if err := g.generateExamples(ctx, &enhancedPkg); err != nil {
-			return "", fmt.Errorf("generate examples: %w", err)
+	// Skip every LLM call when AI is disabled or no LLM is configured
+	if !config.DisableAI && g.llm != nil {
+		// Enhance descriptions with AI
+		outcomes, err := g.enhanceDescriptions(ctx, &enhancedPkg)
+		if err != nil {
+			return nil, fmt.Errorf("enhance descriptions: %w", err)
+		}
+		result.Outcomes = append(result.Outcomes, outcomes...)
+
+		// Generate usage examples if requested
+		if config.GenerateExamples {
+			outcomes, err := g.generateExamples(ctx, &enhancedPkg)
+			if err != nil {
+				return nil, fmt.Errorf("generate examples: %w", err)
+			}
+			result.Outcomes = append(result.Outcomes, outcomes...)
 		}
 	}
 
 	// Apply template based on style
-	result, err := g.templates.Execute(config.Style, &enhancedPkg)
+	content, err := g.templates.Execute(config.Style, &PageData{
+		PackageInfo: &enhancedPkg,
+		RootPath:    config.RootPath,
+		Private:     config.IncludePrivate,
+		Site:        config.Site,
+	})
 	if err != nil {
-		return "", fmt.Errorf("execute template: %w", err)
+		return nil, fmt.Errorf("execute template: %w", err)
 	}
+	result.Content = content
 
 	return result, nil
 }
 
-// enhanceDescriptions enhances package descriptions using AI.
-func (g *Generator) enhanceDescriptions(ctx context.Context, pkg *analyzer.PackageInfo) error {
+// PageData is the data passed to package templates. The embedded package
+// information is accessible directly, e.g. {{.Name}}.
+type PageData struct {
+	*analyzer.PackageInfo
+	RootPath string `json:"root_path"` // relative path to the output directory
+	Private  bool   `json:"private"`   // whether unexported symbols are documented
+	Site     *Site  `json:"-"`         // the other pages of the project, if any
+}
+
+// IndexEntry describes a documented package on the index page.
+type IndexEntry struct {
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	Description string `json:"description"`
+	Link        string `json:"link"` // relative to the index page
+}
+
+// IndexData is the data passed to index templates.
+type IndexData struct {
+	ProjectName string          `json:"project_name"`
+	ProjectDesc string          `json:"project_description"`
+	Packages    []IndexEntry    `json:"packages"`
+	Graph       *analyzer.Graph `json:"graph,omitempty"` // package dependency graph, if embedded
+	MermaidURL  string          `json:"mermaid_url,omitempty"`
+}
+
+// GenerateIndex generates the index page linking every documented package,
+// followed by the package dependency graph when config.Graph is set.
+func (g *Generator) GenerateIndex(entries []IndexEntry, config Config) (string, error) {
+	if err := config.Validate(); err != nil {
+		return "", fmt.Errorf("invalid config: %w", err)
+	}
+
+	content, err := g.templates.ExecuteSection(config.Style, sectionIndex, &IndexData{
+		ProjectName: config.ProjectName,
+		ProjectDesc: config.ProjectDesc,
+		Packages:    entries,
+		Graph:       config.Graph,
+		MermaidURL:  config.MermaidURL,
+	})
+	if err != nil {
+		return "", fmt.Errorf("execute index template: %w", err)
+	}
+
+	return content, nil
+}
+
+// enhanceDescriptions enhances package descriptions using AI and returns the
+// outcome for the package and each of its functions and types.
+func (g *Generator) enhanceDescriptions(ctx context.Context, pkg *analyzer.PackageInfo) ([]Outcome, error) {
+	outcomes := make([]Outcome, 1, 1+len(pkg.Functions)+len(pkg.Types))
+
 	// Enhance package description if empty or too brief
+	pkgSymbol := "package " + pkg.Name
 	if len(pkg.Description) < minDescriptionLength {
-		if enhanced, err := g.enhancePackageDescription(ctx, pkg); err == nil && enhanced != "" {
+		enhanced, cached, err := g.enhancePackageDescription(ctx, pkg)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		outcomes[0] = newOutcome(pkgSymbol, TaskDescription, enhanced, cached, err)
+		if err == nil && enhanced != "" {
 			pkg.Description = enhanced
 		}
+	} else {
+		outcomes[0] = skippedOutcome(pkgSymbol, TaskDescription, "documented")
 	}
 
+	var tasks []func(context.Context)
+
 	// Enhance function descriptions
 	for i := range pkg.Functions {
-		if len(pkg.Functions[i].Description) < minDescriptionLength {
-			if enhanced, err := g.enhanceFunctionDescription(ctx, &pkg.Functions[i]); err == nil && enhanced != "" {
-				pkg.Functions[i].Description = enhanced
-			}
+		fn := &pkg.Functions[i]
+		symbol := functionSymbol(fn)
+		if len(fn.Description) >= minDescriptionLength {
+			outcomes = append(outcomes, skippedOutcome(symbol, TaskDescription, "documented"))
+			continue
 		}
+
+		slot := len(outcomes)
+		outcomes = append(outcomes, Outcome{})
+		tasks = append(tasks, func(ctx context.Context) {
+			enhanced, cached, err := g.enhanceFunctionDescription(ctx, fn)
+			outcomes[slot] = newOutcome(symbol, TaskDescription, enhanced, cached, err)
+			if err == nil && enhanced != "" {
+				fn.Description = enhanced
+			}
+		})
 	}
 
 	// Enhance type descriptions
 	for i := range pkg.Types {
-		if len(pkg.Types[i].Description) < minDescriptionLength {
-			if enhanced, err := g.enhanceTypeDescription(ctx, &pkg.Types[i]); err == nil && enhanced != "" {
-				pkg.Types[i].Description = enhanced
-			}
+		typ := &pkg.Types[i]
+		if len(typ.Description) >= minDescriptionLength {
+			outcomes = append(outcomes, skippedOutcome(typ.Name, TaskDescription, "documented"))
+			continue
 		}
+
+		slot := len(outcomes)
+		outcomes = append(outcomes, Outcome{})
+		tasks = append(tasks, func(ctx context.Context) {
+			enhanced, cached, err := g.enhanceTypeDescription(ctx, typ)
+			outcomes[slot] = newOutcome(typ.Name, TaskDescription, enhanced, cached, err)
+			if err == nil && enhanced != "" {
+				typ.Description = enhanced
+			}
+		})
 	}
 
-	return nil
+	if err := g.runTasks(ctx, tasks); err != nil {
+		return nil, err
+	}
+
+	return outcomes, nil
 }
 
-// generateExamples generates code examples using AI.
-func (g *Generator) generateExamples(ctx context.Context, pkg *analyzer.PackageInfo) error {
+// generateExamples generates code examples using AI and returns the outcome
+// for the package and each of its functions.
+func (g *Generator) generateExamples(ctx context.Context, pkg *analyzer.PackageInfo) ([]Outcome, error) {
+	outcomes := make([]Outcome, 1, 1+len(pkg.Functions))
+
 	// Generate package-level usage example
+	pkgSymbol := "package " + pkg.Name
 	if len(pkg.Examples) == 0 {
-		if example, err := g.generatePackageExample(ctx, pkg); err == nil && example != "" {
+		example, cached, err := g.generatePackageExample(ctx, pkg)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		outcomes[0] = newOutcome(pkgSymbol, TaskExample, example, cached, err)
+		if err == nil && example != "" {
 			pkg.Examples = append(pkg.Examples, analyzer.ExampleInfo{
 				Name: "Basic Usage",
 				Code: example,
 				Doc:  "Basic usage example",
 			})
 		}
+	} else {
+		outcomes[0] = skippedOutcome(pkgSymbol, TaskExample, "has examples")
 	}
 
+	var tasks []func(context.Context)
+
 	// Generate function examples
 	for i := range pkg.Functions {
 		fn := &pkg.Functions[i]
-		if len(fn.Examples) == 0 && fn.IsExported {
-			if example, err := g.generateFunctionExample(ctx, fn, pkg); err == nil && example != "" {
-				fn.Examples = append(fn.Examples, example)
+		symbol := functionSymbol(fn)
+		switch {
+		case !fn.IsExported:
+			outcomes = append(outcomes, skippedOutcome(symbol, TaskExample, "unexported"))
+			continue
+		case fn.Deprecated:
+			outcomes = append(outcomes, skippedOutcome(symbol, TaskExample, "deprecated"))
+			continue
+		case len(fn.Examples) > 0:
+			outcomes = append(outcomes, skippedOutcome(symbol, TaskExample, "has examples"))
+			continue
+		}
+
+		slot := len(outcomes)
+		outcomes = append(outcomes, Outcome{})
+		tasks = append(tasks, func(ctx context.Context) {
+			example, cached, err := g.generateFunctionExample(ctx, fn, pkg)
+			outcomes[slot] = newOutcome(symbol, TaskExample, example, cached, err)
+			if err == nil && example != "" {
+				fn.Examples = append(fn.Examples, analyzer.ExampleInfo{Code: example})
+			}
+		})
+	}
+
+	if err := g.runTasks(ctx, tasks); err != nil {
+		return nil, err
+	}
+
+	return outcomes, nil
+}
+
+// clonePackage returns a copy of pkg that the enhancement tasks can modify
+// without writing to pkg: the functions, types and examples they update are
+// copied rather than shared.
+func clonePackage(pkg *analyzer.PackageInfo) analyzer.PackageInfo {
+	clone := *pkg
+	clone.Examples = slices.Clone(pkg.Examples)
+	clone.Functions = slices.Clone(pkg.Functions)
+	for i := range clone.Functions {
+		clone.Functions[i].Examples = slices.Clone(clone.Functions[i].Examples)
+	}
+	clone.Types = slices.Clone(pkg.Types)
+	return clone
+}
+
+// exportedOnly returns a copy of pkg without unexported functions, methods,
+// types, fields, constants and variables.
+func exportedOnly(pkg analyzer.PackageInfo) analyzer.PackageInfo {
+	var functions []analyzer.FunctionInfo
+	for _, fn := range pkg.Functions {
+		if !fn.IsExported || (fn.Receiver != "" && !ast.IsExported(fn.Receiver)) {
+			continue
+		}
+		// A constructor of an unexported type is listed as a plain function
+		if !ast.IsExported(fn.AssociatedType) {
+			fn.AssociatedType = ""
+		}
+		functions = append(functions, fn)
+	}
+	pkg.Functions = functions
+
+	var types []analyzer.TypeInfo
+	for _, typ := range pkg.Types {
+		if !typ.IsExported {
+			continue
+		}
+
+		var fields []analyzer.FieldInfo
+		for _, field := range typ.Fields {
+			if ast.IsExported(field.Name) {
+				fields = append(fields, field)
+			}
+		}
+		typ.Fields = fields
+
+		var methods []analyzer.InterfaceMethodInfo
+		for _, method := range typ.InterfaceMethods {
+			if ast.IsExported(method.Name) {
+				methods = append(methods, method)
 			}
 		}
+		typ.InterfaceMethods = methods
+		typ.Methods = exportedNames(typ.Methods)
+		typ.Funcs = exportedNames(typ.Funcs)
+		typ.Consts = exportedNames(typ.Consts)
+		typ.Vars = exportedNames(typ.Vars)
+		typ.Implements = exportedInterfaces(typ.Implements)
+		types = append(types, typ)
+	}
+	pkg.Types = types
+
+	var constants []analyzer.ConstantInfo
+	for _, c := range pkg.Constants {
+		if c.IsExported {
+			constants = append(constants, c)
+		}
+	}
+	pkg.Constants = constants
+
+	var variables []analyzer.VariableInfo
+	for _, v := range pkg.Variables {
+		if v.IsExported {
+			variables = append(variables, v)
+		}
+	}
+	pkg.Variables = variables
+
+	pkg.ConstDecls = declaringExported(pkg.ConstDecls)
+	pkg.VarDecls = declaringExported(pkg.VarDecls)
+	return pkg
+}
+
+// exportedNames returns the exported names among names.
+func exportedNames(names []string) []string {
+	var result []string
+	for _, name := range names {
+		if ast.IsExported(name) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// exportedInterfaces filters the interfaces a type implements down to those
+// documented publicly. Interfaces from imports are always exported; those of
+// the package are kept if exported, as is the predeclared error.
+func exportedInterfaces(names []string) []string {
+	var result []string
+	for _, name := range names {
+		if strings.Contains(name, ".") || ast.IsExported(name) || name == "error" {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// declaringExported returns the declaration blocks that declare at least
+// one exported name.
+func declaringExported(decls []analyzer.DeclInfo) []analyzer.DeclInfo {
+	var result []analyzer.DeclInfo
+	for _, decl := range decls {
+		if len(exportedNames(decl.Names)) > 0 {
+			result = append(result, decl)
+		}
+	}
+	return result
+}
+
+// functionSymbol returns the display name of a function or method.
+func functionSymbol(fn *analyzer.FunctionInfo) string {
+	if fn.IsMethod && fn.Receiver != "" {
+		return fn.Receiver + "." + fn.Name
+	}
+	return fn.Name
+}
+
+// runTasks runs tasks on a bounded pool of workers. Each task writes only to
+// its own symbol, so results keep their original order. It stops scheduling
+// new tasks once ctx is done and returns the context's error.
+func (g *Generator) runTasks(ctx context.Context, tasks []func(context.Context)) error {
+	workers := g.concurrency
+	if workers <= 0 {
+		workers = 1
+	}
+
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+
+schedule:
+	for _, task := range tasks {
+		select {
+		case <-ctx.Done():
+			break schedule
+		case sem <- struct{}{}:
+		}
+		// Both cases may be ready once a task cancels ctx
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			task(ctx)
+		}()
 	}
 
-	return nil
+	wg.Wait()
+	return ctx.Err()
 }
 
 // Constants for description enhancement
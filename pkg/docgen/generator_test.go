@@ -0,0 +1,314 @@
+package docgen
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"github.com/docaura/docaura-cli/pkg/analyzer"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// hiddenNames are the symbols of the shapes package left out of its public
+// documentation: unexported or deprecated.
+var hiddenNames = []string{"defaultRadius", "diameter", "unitSquare", "scaleArea", "TotalArea"}
+
+// analyzeShapes analyzes the package in testdata/shapes, unexported symbols
+// included, so that the generator alone decides what is documented.
+func analyzeShapes(t *testing.T) *analyzer.PackageInfo {
+	t.Helper()
+	a := analyzer.New()
+	a.SetIncludePrivate(true)
+	pkg, err := a.AnalyzePackage(filepath.Join("testdata", "shapes"))
+	if err != nil {
+		t.Fatalf("analyze package: %v", err)
+	}
+	return pkg
+}
+
+func TestGeneratePackageDocGolden(t *testing.T) {
+	pkg := analyzeShapes(t)
+
+	tests := []struct {
+		style  string
+		golden string
+	}{
+		{style: "markdown", golden: "shapes.md.golden"},
+		{style: "godoc", golden: "shapes.txt.golden"},
+		{style: "html", golden: "shapes.html.golden"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.style, func(t *testing.T) {
+			llm := NewFakeLLM()
+			generator, err := NewWithLLM(llm)
+			if err != nil {
+				t.Fatalf("create generator: %v", err)
+			}
+
+			result, err := generator.GeneratePackageDoc(context.Background(), pkg, Config{
+				Style:            tt.style,
+				GenerateExamples: true,
+			})
+			if err != nil {
+				t.Fatalf("generate: %v", err)
+			}
+			if failed := result.Failed(); len(failed) > 0 {
+				t.Fatalf("%d enhancements failed, first: %+v", len(failed), failed[0])
+			}
+
+			if len(llm.Prompts()) == 0 {
+				t.Error("no prompts sent to the LLM")
+			}
+			for _, prompt := range llm.Prompts() {
+				for _, name := range hiddenNames {
+					if strings.Contains(prompt, name) {
+						t.Errorf("prompt mentions %s:\n%s", name, prompt)
+					}
+				}
+			}
+
+			if tt.style == "markdown" && strings.Contains(result.Content, "\n\n\n") {
+				t.Error("markdown output has consecutive blank lines")
+			}
+
+			path := filepath.Join("testdata", tt.golden)
+			if *update {
+				if err := os.WriteFile(path, []byte(result.Content), 0644); err != nil {
+					t.Fatalf("write golden file: %v", err)
+				}
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read golden file: %v", err)
+			}
+			if result.Content != string(want) {
+				t.Errorf("%s output differs from %s; rerun with -update and review the diff\ngot:\n%s", tt.style, path, result.Content)
+			}
+		})
+	}
+}
+
+func TestGeneratePackageDocWithoutAI(t *testing.T) {
+	pkg := analyzeShapes(t)
+
+	for _, style := range []string{"markdown", "godoc", "html"} {
+		t.Run(style, func(t *testing.T) {
+			llm := NewFakeLLM()
+			generator, err := NewWithLLM(llm)
+			if err != nil {
+				t.Fatalf("create generator: %v", err)
+			}
+
+			result, err := generator.GeneratePackageDoc(context.Background(), pkg, Config{
+				Style:            style,
+				GenerateExamples: true,
+				IncludePrivate:   true,
+				DisableAI:        true,
+			})
+			if err != nil {
+				t.Fatalf("generate: %v", err)
+			}
+			if prompts := llm.Prompts(); len(prompts) > 0 {
+				t.Errorf("%d prompts sent with AI disabled, first:\n%s", len(prompts), prompts[0])
+			}
+			if len(result.Outcomes) > 0 {
+				t.Errorf("%d enhancement outcomes with AI disabled", len(result.Outcomes))
+			}
+			if !strings.Contains(result.Content, "diameter") {
+				t.Error("private documentation is missing the unexported method diameter")
+			}
+		})
+	}
+}
+
+func TestGeneratePackageDocKeepsInput(t *testing.T) {
+	pkg := analyzeShapes(t)
+	before, err := json.Marshal(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generator, err := NewWithLLM(NewFakeLLM())
+	if err != nil {
+		t.Fatalf("create generator: %v", err)
+	}
+
+	// Both modes run concurrently on the same package
+	var wg sync.WaitGroup
+	for _, private := range []bool{false, true} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := generator.GeneratePackageDoc(context.Background(), pkg, Config{
+				Style:            "markdown",
+				GenerateExamples: true,
+				IncludePrivate:   private,
+			})
+			if err != nil {
+				t.Errorf("generate (private %t): %v", private, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	after, err := json.Marshal(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != string(before) {
+		t.Errorf("GeneratePackageDoc modified its input:\nbefore: %s\nafter:  %s", before, after)
+	}
+}
+
+func TestGeneratePackageDocConcurrencyKeepsOrder(t *testing.T) {
+	pkg := analyzeShapes(t)
+	config := Config{Style: "markdown", GenerateExamples: true}
+
+	generate := func(concurrency int) *Result {
+		generator, err := NewWithLLM(NewFakeLLM())
+		if err != nil {
+			t.Fatalf("create generator: %v", err)
+		}
+		generator.concurrency = concurrency
+
+		result, err := generator.GeneratePackageDoc(context.Background(), pkg, config)
+		if err != nil {
+			t.Fatalf("generate (concurrency %d): %v", concurrency, err)
+		}
+		return result
+	}
+
+	// Hash-derived answers do not depend on the order of the calls, so the
+	// output must match a sequential run
+	want := generate(1)
+	for range 5 {
+		got := generate(8)
+		if got.Content != want.Content {
+			t.Fatal("concurrent run rendered different content than a sequential run")
+		}
+		if len(got.Outcomes) != len(want.Outcomes) {
+			t.Fatalf("got %d outcomes, want %d", len(got.Outcomes), len(want.Outcomes))
+		}
+		for i := range want.Outcomes {
+			if got.Outcomes[i] != want.Outcomes[i] {
+				t.Errorf("outcome %d = %+v, want %+v", i, got.Outcomes[i], want.Outcomes[i])
+			}
+		}
+	}
+}
+
+func TestRunTasks(t *testing.T) {
+	const workers = 3
+	generator := &Generator{concurrency: workers}
+
+	// Later tasks finish first
+	var mu sync.Mutex
+	var running, peak int
+	results := make([]int, 12)
+	tasks := make([]func(context.Context), len(results))
+	for i := range tasks {
+		tasks[i] = func(ctx context.Context) {
+			mu.Lock()
+			running++
+			peak = max(peak, running)
+			mu.Unlock()
+
+			time.Sleep(time.Duration(len(tasks)-i) * time.Millisecond)
+			results[i] = i * i
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}
+	}
+
+	if err := generator.runTasks(context.Background(), tasks); err != nil {
+		t.Fatalf("runTasks: %v", err)
+	}
+	for i, got := range results {
+		if got != i*i {
+			t.Errorf("results[%d] = %d, want %d", i, got, i*i)
+		}
+	}
+	if peak > workers {
+		t.Errorf("%d tasks ran at once, want at most %d", peak, workers)
+	}
+}
+
+func TestRunTasksCancelled(t *testing.T) {
+	generator := &Generator{concurrency: 1}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var ran []int
+	tasks := make([]func(context.Context), 5)
+	for i := range tasks {
+		tasks[i] = func(ctx context.Context) {
+			ran = append(ran, i)
+			if i == 1 {
+				cancel()
+			}
+		}
+	}
+
+	if err := generator.runTasks(ctx, tasks); !errors.Is(err, context.Canceled) {
+		t.Errorf("runTasks = %v, want context.Canceled", err)
+	}
+	if !slices.Equal(ran, []int{0, 1}) {
+		t.Errorf("ran tasks %v, want [0 1]", ran)
+	}
+}
+
+func TestGeneratePackageDocFailingProvider(t *testing.T) {
+	pkg := analyzeShapes(t)
+
+	llm := NewFakeLLM()
+	llm.Fail(errors.New("API returned unexpected status code: 401: invalid API key"))
+	generator, err := NewWithLLM(llm)
+	if err != nil {
+		t.Fatalf("create generator: %v", err)
+	}
+
+	result, err := generator.GeneratePackageDoc(context.Background(), pkg, Config{
+		Style:            "markdown",
+		GenerateExamples: true,
+	})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	failed := result.Failed()
+	if len(failed) == 0 {
+		t.Fatal("no failed outcomes reported for a failing provider")
+	}
+	for _, o := range result.Outcomes {
+		switch o.Status {
+		case OutcomeFailed:
+			if !strings.Contains(o.Reason, "invalid API key") {
+				t.Errorf("%s %s failed with reason %q, want the provider error", o.Symbol, o.Task, o.Reason)
+			}
+		case OutcomeSkipped:
+		default:
+			t.Errorf("%s %s has status %s with a failing provider", o.Symbol, o.Task, o.Status)
+		}
+	}
+
+	// The error is not retryable, so every failed task made a single call
+	if prompts := llm.Prompts(); len(prompts) != len(failed) {
+		t.Errorf("%d prompts sent for %d failed tasks", len(prompts), len(failed))
+	}
+	if !strings.Contains(result.Content, "NewCircle") {
+		t.Error("documentation is not rendered when enhancements fail")
+	}
+}
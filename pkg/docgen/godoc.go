@@ -0,0 +1,164 @@
+package docgen
+
+// godocIndexTemplate renders the plain-text index of every package.
+const godocIndexTemplate = `{{define "index.txt.tmpl"}}{{if .ProjectName}}{{.ProjectName}}{{else}}Packages{{end}}
+{{- if .ProjectDesc}}
+
+{{.ProjectDesc}}
+{{- end}}
+
+PACKAGES
+{{- range .Packages}}
+
+{{.Path}} (package {{.Name}}, see {{.Link}})
+{{- with .Description}}
+{{indent 4 (firstSentence .)}}
+{{- end}}
+{{- end}}
+{{- with .Graph}}
+
+DEPENDENCIES
+
+{{indent 4 (trimSpace (dot .))}}
+{{- end}}
+{{end}}`
+
+// godocTemplate renders plain-text documentation laid out like `go doc -all`.
+const godocTemplate = `package {{.Name}}{{with .ImportPath}} // import "{{.}}"{{end}}
+{{- with .Description}}
+
+{{doc .}}
+{{- end}}
+
+INDEX
+{{if exportedDecls .ConstDecls ""}}
+Constants
+{{- end}}
+{{- if exportedDecls .VarDecls ""}}
+Variables
+{{- end}}
+{{- range plainFuncs .Functions}}
+{{.Signature}}
+{{- end}}
+{{- range .Types}}{{if .IsExported}}
+type {{.Name}}
+{{- range funcsOf $.Functions .Name}}
+    {{.Signature}}
+{{- end}}
+{{- range methodsOf $.Functions .Name}}
+    {{.Signature}}
+{{- end}}
+{{- end}}{{end}}
+{{- with exportedDecls .ConstDecls ""}}
+
+CONSTANTS
+{{- range .}}
+
+{{trimSpace .Source}}
+{{- template "doc.txt.tmpl" .}}
+{{- end}}
+{{- end}}
+{{- with exportedDecls .VarDecls ""}}
+
+VARIABLES
+{{- range .}}
+
+{{trimSpace .Source}}
+{{- template "doc.txt.tmpl" .}}
+{{- end}}
+{{- end}}
+{{- with plainFuncs .Functions}}
+
+FUNCTIONS
+{{- range .}}
+{{template "func.txt.tmpl" .}}
+{{- end}}
+{{- end}}
+{{- if .Types}}
+
+TYPES
+{{- range .Types}}{{if .IsExported}}
+{{template "type.txt.tmpl" .}}
+{{- range exportedDecls $.ConstDecls .Name}}
+
+{{trimSpace .Source}}
+{{- template "doc.txt.tmpl" .}}
+{{- end}}
+{{- range exportedDecls $.VarDecls .Name}}
+
+{{trimSpace .Source}}
+{{- template "doc.txt.tmpl" .}}
+{{- end}}
+{{- range funcsOf $.Functions .Name}}
+{{template "func.txt.tmpl" .}}
+{{- end}}
+{{- range methodsOf $.Functions .Name}}
+{{template "func.txt.tmpl" .}}
+{{- end}}
+{{- end}}{{end}}
+{{- end}}
+{{- if .Private}}
+
+UNEXPORTED
+{{- range unexportedDecls .ConstDecls}}
+
+{{trimSpace .Source}}
+{{- template "doc.txt.tmpl" .}}
+{{- end}}
+{{- range unexportedDecls .VarDecls}}
+
+{{trimSpace .Source}}
+{{- template "doc.txt.tmpl" .}}
+{{- end}}
+{{- range unexportedFuncs .Functions}}
+{{template "func.txt.tmpl" .}}
+{{- end}}
+{{- range unexportedTypes .Types}}
+{{template "type.txt.tmpl" .}}
+{{- range exportedDecls $.ConstDecls .Name}}
+
+{{trimSpace .Source}}
+{{- template "doc.txt.tmpl" .}}
+{{- end}}
+{{- range exportedDecls $.VarDecls .Name}}
+
+{{trimSpace .Source}}
+{{- template "doc.txt.tmpl" .}}
+{{- end}}
+{{- range funcsOf $.Functions .Name}}
+{{template "func.txt.tmpl" .}}
+{{- end}}
+{{- range methodsOf $.Functions .Name}}
+{{template "func.txt.tmpl" .}}
+{{- end}}
+{{- end}}
+{{- range unexportedMethods .Functions}}
+{{template "func.txt.tmpl" .}}
+{{- end}}
+{{- end}}
+{{- with notesOf .Notes "BUG"}}
+
+BUGS
+{{- range .}}
+
+☞ {{indent 2 .Body | trimSpace}}
+{{- end}}
+{{- end}}
+{{define "type.txt.tmpl"}}
+{{if .Decl}}{{trimSpace .Decl}}{{else}}type {{.Name}}{{typeParams .TypeParams}} {{.Kind}}{{constraintBody .Constraint}}{{end}}
+{{- template "doc.txt.tmpl" .}}
+{{- end}}
+{{- define "func.txt.tmpl"}}
+{{.Signature}}
+{{- template "doc.txt.tmpl" .}}
+{{- end}}
+{{- define "doc.txt.tmpl"}}
+{{- with .Description}}
+{{indent 4 (doc .)}}
+{{- end}}
+{{- if .Deprecated}}
+{{- if .Description}}
+{{end}}
+{{indent 4 (doc (printf "Deprecated: %s" .DeprecationMessage))}}
+{{- end}}
+{{- end}}`
@@ -0,0 +1,162 @@
+package docgen
+
+import (
+	"fmt"
+	"github.com/docaura/docaura-cli/pkg/analyzer"
+	"strconv"
+	"strings"
+)
+
+// Colors of the package dependency graph, shared by its DOT and Mermaid
+// renderings.
+const (
+	graphModuleFill       = "#dbeafe"
+	graphModuleStroke     = "#3b82f6"
+	graphStdlibFill       = "#f3f4f6"
+	graphStdlibStroke     = "#9ca3af"
+	graphThirdPartyFill   = "#fde7c8"
+	graphThirdPartyStroke = "#e59a2b"
+	graphCycleStroke      = "#dc2626"
+)
+
+// RenderGraph renders a package dependency graph as Graphviz DOT ("dot") or
+// as a Mermaid flowchart ("mermaid"). Module packages are drawn as boxes,
+// dashed under internal/ directories, and standard library and third-party
+// packages as rounded nodes of their own colors. Import cycles are drawn in
+// red.
+func RenderGraph(g *analyzer.Graph, format string) (string, error) {
+	if err := ValidateGraphFormat(format); err != nil {
+		return "", err
+	}
+	if format == "mermaid" {
+		return mermaidGraph(g), nil
+	}
+	return dotGraph(g), nil
+}
+
+// ValidateGraphFormat checks that format is a graph format supported by
+// RenderGraph.
+func ValidateGraphFormat(format string) error {
+	if format != "dot" && format != "mermaid" {
+		return fmt.Errorf("invalid graph format %q: must be one of dot, mermaid", format)
+	}
+	return nil
+}
+
+// dotGraph renders a package dependency graph as Graphviz DOT.
+func dotGraph(g *analyzer.Graph) string {
+	var sb strings.Builder
+	sb.WriteString("digraph imports {\n")
+	sb.WriteString("\trankdir=LR;\n")
+	sb.WriteString("\tnode [fontname=\"Helvetica\", fontsize=10, style=filled];\n")
+	sb.WriteString("\tedge [color=\"#6b7280\"];\n")
+
+	for _, node := range g.Nodes {
+		shape, fill, stroke := "ellipse", graphThirdPartyFill, graphThirdPartyStroke
+		switch node.Kind {
+		case analyzer.ImportModule:
+			shape, fill, stroke = "box", graphModuleFill, graphModuleStroke
+		case analyzer.ImportStdlib:
+			fill, stroke = graphStdlibFill, graphStdlibStroke
+		}
+
+		attrs := []string{"label=" + strconv.Quote(node.Label), "shape=" + shape, dotColor("fillcolor", fill)}
+		if node.InCycle {
+			attrs = append(attrs, dotColor("color", graphCycleStroke), "penwidth=2")
+		} else {
+			attrs = append(attrs, dotColor("color", stroke))
+		}
+		if node.Internal {
+			attrs = append(attrs, `style="filled,dashed"`)
+		}
+		fmt.Fprintf(&sb, "\t%s [%s];\n", strconv.Quote(node.ImportPath), strings.Join(attrs, ", "))
+	}
+
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&sb, "\t%s -> %s", strconv.Quote(edge.From), strconv.Quote(edge.To))
+		if edge.InCycle {
+			fmt.Fprintf(&sb, " [%s, penwidth=2]", dotColor("color", graphCycleStroke))
+		}
+		sb.WriteString(";\n")
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// dotColor formats a color attribute.
+func dotColor(attr, color string) string {
+	return attr + "=" + strconv.Quote(color)
+}
+
+// mermaidGraph renders a package dependency graph as a Mermaid flowchart.
+// Nodes are given short IDs, since Mermaid IDs cannot contain slashes.
+func mermaidGraph(g *analyzer.Graph) string {
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+
+	ids := make(map[string]string, len(g.Nodes))
+	classes := make(map[string][]string)
+	var classOrder []string
+	addClass := func(class, id string) {
+		if _, ok := classes[class]; !ok {
+			classOrder = append(classOrder, class)
+		}
+		classes[class] = append(classes[class], id)
+	}
+
+	for i, node := range g.Nodes {
+		id := fmt.Sprintf("n%d", i)
+		ids[node.ImportPath] = id
+
+		label := strings.ReplaceAll(node.Label, `"`, "#quot;")
+		switch node.Kind {
+		case analyzer.ImportModule:
+			fmt.Fprintf(&sb, "    %s[\"%s\"]\n", id, label)
+			if node.Internal {
+				addClass("internal", id)
+			} else {
+				addClass("module", id)
+			}
+		case analyzer.ImportStdlib:
+			fmt.Fprintf(&sb, "    %s([\"%s\"])\n", id, label)
+			addClass("stdlib", id)
+		default:
+			fmt.Fprintf(&sb, "    %s([\"%s\"])\n", id, label)
+			addClass("thirdparty", id)
+		}
+	}
+
+	var cycleLinks []string
+	for i, edge := range g.Edges {
+		fmt.Fprintf(&sb, "    %s --> %s\n", ids[edge.From], ids[edge.To])
+		if edge.InCycle {
+			cycleLinks = append(cycleLinks, strconv.Itoa(i))
+		}
+	}
+
+	fmt.Fprintf(&sb, "    classDef module fill:%s,stroke:%s\n", graphModuleFill, graphModuleStroke)
+	fmt.Fprintf(&sb, "    classDef internal fill:%s,stroke:%s,stroke-dasharray:4 2\n", graphModuleFill, graphModuleStroke)
+	fmt.Fprintf(&sb, "    classDef stdlib fill:%s,stroke:%s\n", graphStdlibFill, graphStdlibStroke)
+	fmt.Fprintf(&sb, "    classDef thirdparty fill:%s,stroke:%s\n", graphThirdPartyFill, graphThirdPartyStroke)
+	fmt.Fprintf(&sb, "    classDef cycle stroke:%s,stroke-width:2px\n", graphCycleStroke)
+	for _, class := range classOrder {
+		fmt.Fprintf(&sb, "    class %s %s\n", strings.Join(classes[class], ","), class)
+	}
+
+	// Assigned last so that the cycle stroke wins over the kind's
+	var cycleNodes []string
+	for _, node := range g.Nodes {
+		if node.InCycle {
+			cycleNodes = append(cycleNodes, ids[node.ImportPath])
+		}
+	}
+	if len(cycleNodes) > 0 {
+		fmt.Fprintf(&sb, "    class %s cycle\n", strings.Join(cycleNodes, ","))
+	}
+	if len(cycleLinks) > 0 {
+		fmt.Fprintf(&sb, "    linkStyle %s stroke:%s,stroke-width:2px\n", strings.Join(cycleLinks, ","), graphCycleStroke)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,96 @@
+package docgen
+
+import (
+	"github.com/docaura/docaura-cli/pkg/analyzer"
+	"testing"
+)
+
+// cycleGraph is a small graph where a and internal/c import each other.
+var cycleGraph = &analyzer.Graph{
+	Nodes: []analyzer.GraphNode{
+		{ImportPath: "example.com/m", Label: "m", Kind: analyzer.ImportModule},
+		{ImportPath: "example.com/m/a", Label: "a", Kind: analyzer.ImportModule, InCycle: true},
+		{ImportPath: "example.com/m/internal/c", Label: "internal/c", Kind: analyzer.ImportModule, Internal: true, InCycle: true},
+		{ImportPath: "github.com/acme/log", Label: "github.com/acme/log", Kind: analyzer.ImportThirdParty},
+		{ImportPath: "strings", Label: "strings", Kind: analyzer.ImportStdlib},
+	},
+	Edges: []analyzer.GraphEdge{
+		{From: "example.com/m", To: "example.com/m/a"},
+		{From: "example.com/m/a", To: "example.com/m/internal/c", InCycle: true},
+		{From: "example.com/m/a", To: "strings"},
+		{From: "example.com/m/internal/c", To: "example.com/m/a", InCycle: true},
+		{From: "example.com/m/internal/c", To: "github.com/acme/log"},
+	},
+	Cycles: [][]string{{"example.com/m/a", "example.com/m/internal/c"}},
+}
+
+func TestRenderGraph(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: "dot",
+			want: `digraph imports {
+	rankdir=LR;
+	node [fontname="Helvetica", fontsize=10, style=filled];
+	edge [color="#6b7280"];
+	"example.com/m" [label="m", shape=box, fillcolor="#dbeafe", color="#3b82f6"];
+	"example.com/m/a" [label="a", shape=box, fillcolor="#dbeafe", color="#dc2626", penwidth=2];
+	"example.com/m/internal/c" [label="internal/c", shape=box, fillcolor="#dbeafe", color="#dc2626", penwidth=2, style="filled,dashed"];
+	"github.com/acme/log" [label="github.com/acme/log", shape=ellipse, fillcolor="#fde7c8", color="#e59a2b"];
+	"strings" [label="strings", shape=ellipse, fillcolor="#f3f4f6", color="#9ca3af"];
+	"example.com/m" -> "example.com/m/a";
+	"example.com/m/a" -> "example.com/m/internal/c" [color="#dc2626", penwidth=2];
+	"example.com/m/a" -> "strings";
+	"example.com/m/internal/c" -> "example.com/m/a" [color="#dc2626", penwidth=2];
+	"example.com/m/internal/c" -> "github.com/acme/log";
+}
+`,
+		},
+		{
+			format: "mermaid",
+			want: `flowchart LR
+    n0["m"]
+    n1["a"]
+    n2["internal/c"]
+    n3(["github.com/acme/log"])
+    n4(["strings"])
+    n0 --> n1
+    n1 --> n2
+    n1 --> n4
+    n2 --> n1
+    n2 --> n3
+    classDef module fill:#dbeafe,stroke:#3b82f6
+    classDef internal fill:#dbeafe,stroke:#3b82f6,stroke-dasharray:4 2
+    classDef stdlib fill:#f3f4f6,stroke:#9ca3af
+    classDef thirdparty fill:#fde7c8,stroke:#e59a2b
+    classDef cycle stroke:#dc2626,stroke-width:2px
+    class n0,n1 module
+    class n2 internal
+    class n3 thirdparty
+    class n4 stdlib
+    class n1,n2 cycle
+    linkStyle 1,3 stroke:#dc2626,stroke-width:2px
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			got, err := RenderGraph(cycleGraph, tt.format)
+			if err != nil {
+				t.Fatalf("RenderGraph: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RenderGraph(%s) =\n%s\nwant:\n%s", tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderGraphInvalidFormat(t *testing.T) {
+	if _, err := RenderGraph(cycleGraph, "svg"); err == nil {
+		t.Error("RenderGraph accepted the unsupported format svg")
+	}
+}
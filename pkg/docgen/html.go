@@ -0,0 +1,483 @@
+package docgen
+
+import (
+	"go/token"
+	"html"
+	htmltemplate "html/template"
+	"maps"
+	"strings"
+)
+
+// htmlFuncMap returns the helper functions available to HTML templates.
+func htmlFuncMap() htmltemplate.FuncMap {
+	funcMap := htmltemplate.FuncMap(baseFuncMap())
+	funcMap["highlight"] = highlightGo
+	funcMap["css"] = func() htmltemplate.CSS { return htmltemplate.CSS(htmlTheme) }
+	maps.Copy(funcMap, newDocRenderer(nil).funcs("html"))
+	return funcMap
+}
+
+// builtinTypes lists the predeclared Go types highlighted as types.
+var builtinTypes = map[string]bool{
+	"any": true, "bool": true, "byte": true, "comparable": true,
+	"complex64": true, "complex128": true, "error": true,
+	"float32": true, "float64": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"rune": true, "string": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+}
+
+// highlightGo renders Go source as HTML with keywords, builtin types,
+// literals and comments wrapped in classed spans.
+func highlightGo(src string) htmltemplate.HTML {
+	return highlightLinked(src, nil)
+}
+
+// highlightLinked is highlightGo with the given references, in source order,
+// wrapped in links.
+func highlightLinked(src string, refs []reference) htmltemplate.HTML {
+	var sb strings.Builder
+	last := 0
+
+	for _, t := range scanGo(src) {
+		sb.WriteString(html.EscapeString(src[last:t.start]))
+		if len(refs) > 0 && t.start == refs[0].start {
+			sb.WriteString(`<a href="` + html.EscapeString(refs[0].url) + `">`)
+		}
+
+		text := src[t.start:t.end]
+		if class := tokenClass(t.tok, t.lit); class != "" {
+			sb.WriteString(`<span class="` + class + `">`)
+			sb.WriteString(html.EscapeString(text))
+			sb.WriteString(`</span>`)
+		} else {
+			sb.WriteString(html.EscapeString(text))
+		}
+
+		if len(refs) > 0 && t.end == refs[0].end {
+			sb.WriteString("</a>")
+			refs = refs[1:]
+		}
+		last = t.end
+	}
+
+	sb.WriteString(html.EscapeString(src[last:]))
+	return htmltemplate.HTML(sb.String())
+}
+
+// tokenClass returns the CSS class used to highlight a token.
+func tokenClass(tok token.Token, lit string) string {
+	switch {
+	case tok.IsKeyword():
+		return "kw"
+	case tok == token.IDENT && builtinTypes[lit]:
+		return "ty"
+	case tok == token.COMMENT:
+		return "com"
+	case tok.IsLiteral() && tok != token.IDENT:
+		return "lit"
+	default:
+		return ""
+	}
+}
+
+// htmlTheme is the stylesheet embedded into every HTML page.
+const htmlTheme = `
+:root {
+  --bg: #ffffff; --fg: #1f2328; --muted: #59636e; --border: #d1d9e0;
+  --sidebar: #f6f8fa; --link: #0969da; --code-bg: #f6f8fa;
+  --kw: #cf222e; --ty: #8250df; --lit: #0a3069; --com: #6e7781;
+}
+@media (prefers-color-scheme: dark) {
+  :root {
+    --bg: #0d1117; --fg: #e6edf3; --muted: #9198a1; --border: #3d444d;
+    --sidebar: #151b23; --link: #4493f8; --code-bg: #151b23;
+    --kw: #ff7b72; --ty: #d2a8ff; --lit: #a5d6ff; --com: #9198a1;
+  }
+}
+* { box-sizing: border-box; }
+body {
+  margin: 0; display: flex; min-height: 100vh;
+  font: 15px/1.6 -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif;
+  color: var(--fg); background: var(--bg);
+}
+a { color: var(--link); text-decoration: none; }
+a:hover { text-decoration: underline; }
+.sidebar {
+  position: sticky; top: 0; align-self: flex-start; height: 100vh; overflow-y: auto;
+  width: 280px; flex-shrink: 0; padding: 1.5rem 1rem;
+  background: var(--sidebar); border-right: 1px solid var(--border);
+}
+.sidebar h2 { margin: 0.5rem 0; font-size: 1.2rem; }
+.sidebar h3 { margin: 1rem 0 0.25rem; font-size: 0.8rem; text-transform: uppercase; color: var(--muted); }
+.sidebar ul { list-style: none; margin: 0; padding: 0; }
+.sidebar li { margin: 0.1rem 0; font-family: ui-monospace, SFMono-Regular, Menlo, monospace; font-size: 0.85rem; }
+.sidebar li li { padding-left: 1rem; }
+.sidebar .home { font-size: 0.85rem; }
+main { flex: 1; max-width: 960px; padding: 2rem 3rem; }
+h1 { margin-top: 0; border-bottom: 1px solid var(--border); padding-bottom: 0.5rem; }
+h2 { margin-top: 2.5rem; border-bottom: 1px solid var(--border); padding-bottom: 0.3rem; }
+h3 { margin-top: 2rem; }
+h3 .anchor, h4 .anchor { visibility: hidden; margin-left: 0.4rem; color: var(--muted); }
+h3:hover .anchor, h4:hover .anchor { visibility: visible; }
+pre, code { font-family: ui-monospace, SFMono-Regular, Menlo, monospace; font-size: 0.875rem; }
+pre { background: var(--code-bg); border: 1px solid var(--border); border-radius: 6px; padding: 0.75rem 1rem; overflow-x: auto; }
+.kw { color: var(--kw); }
+.ty { color: var(--ty); }
+.lit { color: var(--lit); }
+.com { color: var(--com); font-style: italic; }
+.muted { color: var(--muted); }
+.badge { display: inline-block; padding: 0 0.5rem; border-radius: 1rem; font-size: 0.75rem; font-weight: 600; color: #fff; background: var(--kw); }
+.deprecated { color: var(--muted); margin: 1rem 0; }
+.deprecated > p:first-of-type { display: inline; }
+.badge.build { background: var(--muted); }
+.badge.cycle { background: #dc2626; }
+code.cycle { color: #dc2626; }
+table { border-collapse: collapse; width: 100%; margin: 0.5rem 0 1rem; }
+th, td { border: 1px solid var(--border); padding: 0.35rem 0.6rem; text-align: left; vertical-align: top; }
+th { background: var(--sidebar); }
+.packages dt { margin-top: 1rem; font-family: ui-monospace, SFMono-Regular, Menlo, monospace; }
+.packages dd { margin: 0.25rem 0 0 1rem; color: var(--muted); }
+`
+
+// htmlPackageTemplate renders a single package page.
+const htmlPackageTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Name}} - Go package documentation</title>
+<style>{{css}}</style>
+</head>
+<body>
+<nav class="sidebar">
+<a class="home" href="{{.RootPath}}/index.html">&larr; All packages</a>
+<h2><a href="#pkg-overview">{{.Name}}</a></h2>
+<ul>
+<li><a href="#pkg-overview">Overview</a></li>
+{{- if .Examples}}
+<li><a href="#pkg-examples">Examples</a></li>
+{{- end}}
+{{- if .Notes}}
+<li><a href="#pkg-notes">Known issues</a></li>
+{{- end}}
+{{- if .Private}}
+<li><a href="#pkg-unexported">Unexported symbols</a></li>
+{{- end}}
+</ul>
+{{- with exportedDecls .ConstDecls ""}}
+<h3>Constants</h3>
+<ul>
+{{- range .}}{{range exportedNames .Names}}
+<li><a href="#const-{{.}}">{{.}}</a></li>
+{{- end}}{{end}}
+</ul>
+{{- end}}
+{{- with exportedDecls .VarDecls ""}}
+<h3>Variables</h3>
+<ul>
+{{- range .}}{{range exportedNames .Names}}
+<li><a href="#var-{{.}}">{{.}}</a></li>
+{{- end}}{{end}}
+</ul>
+{{- end}}
+{{- with plainFuncs .Functions}}
+<h3>Functions</h3>
+<ul>
+{{- range .}}
+<li><a href="#func-{{.Name}}">{{.Name}}</a></li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .Types}}
+<h3>Types</h3>
+<ul>
+{{- range .Types}}{{if .IsExported}}
+<li><a href="#type-{{.Name}}">{{.Name}}</a>
+{{- if or (funcsOf $.Functions .Name) (methodsOf $.Functions .Name)}}
+<ul>
+{{- range funcsOf $.Functions .Name}}
+<li><a href="#func-{{.Name}}">{{.Name}}</a></li>
+{{- end}}
+{{- range methodsOf $.Functions .Name}}
+<li><a href="#method-{{.Receiver}}-{{.Name}}">{{.Name}}</a></li>
+{{- end}}
+</ul>
+{{- end}}
+</li>
+{{- end}}{{end}}
+</ul>
+{{- end}}
+</nav>
+<main>
+<h1 id="pkg-overview">package {{.Name}}</h1>
+{{- with .ImportPath}}
+<pre>{{highlight (printf "import %q" .)}}</pre>
+<p class="muted">{{if eq $.Name "main"}}<code>go install {{.}}@{{or $.Module.Version "latest"}}</code>{{else}}<code>go get {{.}}{{with $.Module.Version}}@{{.}}{{end}}</code>{{end}}
+{{- with $.Module.GoVersion}} &middot; Requires Go {{.}} or later{{end}}</p>
+{{- end}}
+{{- if .Description}}
+{{doc .Description}}
+{{- end}}
+
+{{- if .Examples}}
+<h2 id="pkg-examples">Examples</h2>
+{{- range .Examples}}
+<h4>{{.Name}}</h4>
+{{template "example.html.tmpl" .}}
+{{- end}}
+{{- end}}
+
+{{- with .Notes}}
+<h2 id="pkg-notes">Known issues</h2>
+<ul>
+{{- range .}}
+<li><strong>{{.Marker}}</strong>{{if .UID}}({{.UID}}){{end}}: {{.Body}}</li>
+{{- end}}
+</ul>
+{{- end}}
+
+{{- with exportedDecls .ConstDecls ""}}
+<h2 id="pkg-constants">Constants</h2>
+{{- range .}}
+{{template "decl.html.tmpl" .}}
+{{- end}}
+{{- end}}
+
+{{- with exportedDecls .VarDecls ""}}
+<h2 id="pkg-variables">Variables</h2>
+{{- range .}}
+{{template "decl.html.tmpl" .}}
+{{- end}}
+{{- end}}
+
+{{- with plainFuncs .Functions}}
+<h2 id="pkg-functions">Functions</h2>
+{{- range .}}
+{{template "func.html.tmpl" .}}
+{{- end}}
+{{- end}}
+
+{{- if .Types}}
+<h2 id="pkg-types">Types</h2>
+{{- range .Types}}{{if .IsExported}}
+{{template "type.html.tmpl" .}}
+{{- range exportedDecls $.ConstDecls .Name}}
+{{template "decl.html.tmpl" .}}
+{{- end}}
+{{- range exportedDecls $.VarDecls .Name}}
+{{template "decl.html.tmpl" .}}
+{{- end}}
+{{- range funcsOf $.Functions .Name}}
+{{template "func.html.tmpl" .}}
+{{- end}}
+{{- range methodsOf $.Functions .Name}}
+{{template "func.html.tmpl" .}}
+{{- end}}
+{{- end}}{{end}}
+{{- end}}
+
+{{- if .Private}}
+<h2 id="pkg-unexported">Unexported symbols</h2>
+<p class="muted">These symbols are not part of the package's public API.</p>
+{{- range unexportedDecls .ConstDecls}}
+{{template "decl.html.tmpl" .}}
+{{- end}}
+{{- range unexportedDecls .VarDecls}}
+{{template "decl.html.tmpl" .}}
+{{- end}}
+{{- range unexportedFuncs .Functions}}
+{{template "func.html.tmpl" .}}
+{{- end}}
+{{- range unexportedTypes .Types}}
+{{template "type.html.tmpl" .}}
+{{- range exportedDecls $.ConstDecls .Name}}
+{{template "decl.html.tmpl" .}}
+{{- end}}
+{{- range exportedDecls $.VarDecls .Name}}
+{{template "decl.html.tmpl" .}}
+{{- end}}
+{{- range funcsOf $.Functions .Name}}
+{{template "func.html.tmpl" .}}
+{{- end}}
+{{- range methodsOf $.Functions .Name}}
+{{template "func.html.tmpl" .}}
+{{- end}}
+{{- end}}
+{{- range unexportedMethods .Functions}}
+{{template "func.html.tmpl" .}}
+{{- end}}
+{{- end}}
+</main>
+</body>
+</html>
+{{define "type.html.tmpl"}}<h3 id="type-{{.Name}}">type {{.Name}}<a class="anchor" href="#type-{{.Name}}">#</a></h3>
+<pre>{{highlight (or (trimSpace .Decl) (printf "type %s%s %s%s" .Name (typeParams .TypeParams) .Kind (constraintBody .Constraint)))}}</pre>
+{{- template "deprecated.html.tmpl" .}}
+{{- template "build.html.tmpl" .}}
+{{- if .Description}}
+{{doc .Description}}
+{{- end}}
+{{- if .Fields}}
+<table>
+<thead><tr><th>Field</th><th>Type</th><th>Tags</th><th>Description</th></tr></thead>
+<tbody>
+{{- range .Fields}}
+<tr><td><code>{{.Name}}</code>{{if .Embedded}} <span class="muted">(embedded)</span>{{end}}</td><td>{{linkType .Type .QualifiedType}}</td><td>{{range $key, $value := .Tags}}<code>{{$key}}:{{printf "%q" $value}}</code> {{end}}</td><td>{{.Description}}{{if .Promoted}} <span class="muted">Promotes {{range $i, $m := .Promoted}}{{if $i}}, {{end}}<code>{{$m}}</code>{{end}}.</span>{{end}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+{{- with .Enum}}
+<p>Values{{if .HasString}} <span class="muted">(printed by name via <code>String()</code>)</span>{{end}}:</p>
+<table>
+<thead><tr><th>Constant</th><th>Value</th><th>Description</th></tr></thead>
+<tbody>
+{{- range .Members}}
+<tr><td><a href="#const-{{.Name}}"><code>{{.Name}}</code></a></td><td><code>{{.Value}}</code></td><td>{{.Description}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+{{- if .Embeds}}
+<p>Embeds: {{range $i, $embed := .Embeds}}{{if $i}}, {{end}}{{linkType $embed}}{{end}}</p>
+{{- end}}
+{{- if .InterfaceMethods}}
+<table>
+<thead><tr><th>Method</th><th>Description</th></tr></thead>
+<tbody>
+{{- range .InterfaceMethods}}
+<tr><td><code>{{.Signature}}</code></td><td>{{.Description}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+{{- range separateExamples .Examples}}
+<p class="muted">Example{{if .Suffix}} ({{.Suffix}}){{end}}:</p>
+{{template "example.html.tmpl" .}}
+{{- end}}
+{{- if .Implements}}
+<p>Implements: {{range $i, $iface := .Implements}}{{if $i}}, {{end}}{{linkType $iface}}{{end}}</p>
+{{- end}}
+{{- if .PromotedMethods}}
+<p>Promoted methods:</p>
+<ul>
+{{- range .PromotedMethods}}
+<li><code>{{.Name}}{{trimPrefix "func" .Signature}}</code> <span class="muted">from {{.From}}</span></li>
+{{- end}}
+</ul>
+{{- end}}
+{{- end}}
+{{- define "func.html.tmpl"}}
+{{- if .IsMethod}}
+<h4 id="method-{{.Receiver}}-{{.Name}}">func ({{.Receiver}}) {{.Name}}<a class="anchor" href="#method-{{.Receiver}}-{{.Name}}">#</a></h4>
+{{- else}}
+<h3 id="func-{{.Name}}">func {{.Name}}<a class="anchor" href="#func-{{.Name}}">#</a></h3>
+{{- end}}
+<pre>{{highlight .Signature}}</pre>
+{{- template "deprecated.html.tmpl" .}}
+{{- template "build.html.tmpl" .}}
+{{- if .Description}}
+{{doc .Description}}
+{{- end}}
+{{- if .Parameters}}
+<table>
+<thead><tr><th>Parameter</th><th>Type</th></tr></thead>
+<tbody>
+{{- range .Parameters}}
+<tr><td><code>{{.Name}}</code></td><td>{{linkType .Type .QualifiedType}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+{{- range separateExamples .Examples}}
+<p class="muted">Example{{if .Suffix}} ({{.Suffix}}){{end}}:</p>
+{{template "example.html.tmpl" .}}
+{{- end}}
+{{- end}}
+{{- define "decl.html.tmpl"}}
+{{- $kind := declKind .}}
+<pre>{{range .Names}}{{if ne . "_"}}<span id="{{$kind}}-{{.}}"></span>{{end}}{{end}}{{highlight (trimSpace .Source)}}</pre>
+{{- template "deprecated.html.tmpl" .}}
+{{- template "build.html.tmpl" .}}
+{{- if .Description}}
+{{doc .Description}}
+{{- end}}
+{{- end}}
+{{- define "deprecated.html.tmpl"}}
+{{- if .Deprecated}}
+<div class="deprecated"><span class="badge">Deprecated</span> {{with .DeprecationMessage}}{{doc .}}{{end}}</div>
+{{- end}}
+{{- end}}
+{{- define "build.html.tmpl"}}
+{{- if .BuildConstraint}}
+<p><span class="badge build">{{buildNote .BuildConstraint}}</span></p>
+{{- end}}
+{{- end}}
+{{- define "example.html.tmpl"}}
+{{- if .Doc}}
+{{doc .Doc}}
+{{- end}}
+<pre>{{highlight .Code}}</pre>
+{{- if .Output}}
+<p class="muted">Output:</p>
+<pre>{{trimSpace .Output}}</pre>
+{{- end}}
+{{- end}}
+`
+
+// htmlIndexTemplate renders the index page linking every package.
+const htmlIndexTemplate = `{{define "index.html.tmpl"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{if .ProjectName}}{{.ProjectName}}{{else}}Packages{{end}} - Go documentation</title>
+<style>{{css}}</style>
+</head>
+<body>
+<nav class="sidebar">
+<h2><a href="index.html">{{if .ProjectName}}{{.ProjectName}}{{else}}Packages{{end}}</a></h2>
+<h3>Packages</h3>
+<ul>
+{{- range .Packages}}
+<li><a href="{{.Link}}">{{.Name}}</a></li>
+{{- end}}
+</ul>
+{{- if .Graph}}
+<h3><a href="#pkg-dependencies">Dependencies</a></h3>
+{{- end}}
+</nav>
+<main>
+<h1>{{if .ProjectName}}{{.ProjectName}}{{else}}Packages{{end}}</h1>
+{{- if .ProjectDesc}}
+<p>{{.ProjectDesc}}</p>
+{{- end}}
+<h2>Packages</h2>
+<dl class="packages">
+{{- range .Packages}}
+<dt id="import-{{.Path}}"><a href="{{.Link}}">{{.Name}}</a> <span class="muted">{{.Path}}</span></dt>
+<dd>{{firstSentence .Description}}</dd>
+{{- end}}
+</dl>
+{{- with $graph := .Graph}}
+<h2 id="pkg-dependencies">Dependencies</h2>
+{{- if $.MermaidURL}}
+<pre class="mermaid">{{mermaid .}}</pre>
+<script type="module">
+import mermaid from "{{$.MermaidURL}}";
+mermaid.initialize({startOnLoad: true});
+</script>
+{{- else}}
+<dl class="packages">
+{{- range .Nodes}}{{if eq .Kind "module"}}
+<dt>{{.Label}}{{if .InCycle}} <span class="badge cycle">import cycle</span>{{end}}</dt>
+<dd>{{range $i, $edge := $graph.Imports .ImportPath}}{{if $i}}, {{end}}<code{{if $edge.InCycle}} class="cycle"{{end}}>{{($graph.Node $edge.To).Label}}</code>{{else}}no imports{{end}}</dd>
+{{- end}}{{end}}
+</dl>
+{{- end}}
+{{- end}}
+</main>
+</body>
+</html>
+{{end}}`
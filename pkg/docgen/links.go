@@ -0,0 +1,392 @@
+package docgen
+
+import (
+	"github.com/docaura/docaura-cli/pkg/analyzer"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"html"
+	htmltemplate "html/template"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Site describes the pages generated for a project, so that the page of one
+// package can link to the symbols of the others.
+type Site struct {
+	Project *analyzer.Project
+	Pages   map[string]string // page of each package by import path, relative to the output directory
+}
+
+// goToken is a token of Go source along with its byte offsets.
+type goToken struct {
+	tok        token.Token
+	lit        string
+	start, end int
+}
+
+// scanGo splits Go source into tokens, leaving out the semicolons inserted
+// automatically at line ends.
+func scanGo(src string) []goToken {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+
+	var s scanner.Scanner
+	s.Init(file, []byte(src), nil, scanner.ScanComments)
+
+	var tokens []goToken
+	last := 0
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok == token.SEMICOLON && lit == "\n" {
+			continue
+		}
+
+		text := lit
+		if text == "" {
+			text = tok.String()
+		}
+
+		start := file.Offset(pos)
+		end := start + len(text)
+		if start < last || end > len(src) {
+			continue
+		}
+		tokens = append(tokens, goToken{tok: tok, lit: lit, start: start, end: end})
+		last = end
+	}
+	return tokens
+}
+
+// reference is a span of Go source naming a documented symbol.
+type reference struct {
+	start, end int
+	url        string
+}
+
+// findReferences returns the identifiers of src in type positions, qualified
+// like "analyzer.PackageInfo" or not, for which url returns a link. url
+// receives the package qualifier, empty for unqualified identifiers, and the
+// name. src may be declarations, an expression or type, or statements; names
+// being declared, fields, methods and selectors are never looked up.
+func findReferences(src string, url func(qualifier, name string) string) []reference {
+	positions := typePositions(src)
+	if len(positions) == 0 {
+		return nil
+	}
+	tokens := scanGo(src)
+
+	var refs []reference
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		if t.tok != token.IDENT || !positions[t.start] {
+			continue
+		}
+
+		if i+2 < len(tokens) && tokens[i+1].tok == token.PERIOD && tokens[i+2].tok == token.IDENT {
+			name := tokens[i+2]
+			if link := url(t.lit, name.lit); link != "" {
+				refs = append(refs, reference{start: t.start, end: name.end, url: link})
+			}
+			i += 2
+			continue
+		}
+		if link := url("", t.lit); link != "" {
+			refs = append(refs, reference{start: t.start, end: t.end, url: link})
+		}
+	}
+	return refs
+}
+
+// typePositions parses src and returns the offsets of the identifiers it
+// uses as types, or as the package qualifiers of qualified types. Calls of
+// plain or qualified identifiers count, as they may be conversions. It
+// returns nil if src does not parse.
+func typePositions(src string) map[int]bool {
+	positions := make(map[int]bool)
+
+	var root ast.Node
+	var base int
+	fset := token.NewFileSet()
+	if expr, err := parser.ParseExprFrom(fset, "", src, 0); err == nil {
+		root, base = expr, fset.File(expr.Pos()).Base()
+		typeIdents(expr, base, positions)
+	} else {
+		for _, wrap := range [][2]string{{"package p\n", ""}, {"package p\nfunc _() {\n", "\n}"}} {
+			file, err := parser.ParseFile(fset, "", wrap[0]+src+wrap[1], 0)
+			if err == nil {
+				root, base = file, fset.File(file.Pos()).Base()+len(wrap[0])
+				break
+			}
+		}
+		if root == nil {
+			return nil
+		}
+	}
+
+	mark := func(expr ast.Expr) {
+		if expr != nil {
+			typeIdents(expr, base, positions)
+		}
+	}
+	markFields := func(fields *ast.FieldList) {
+		if fields != nil {
+			for _, field := range fields.List {
+				mark(field.Type)
+			}
+		}
+	}
+
+	ast.Inspect(root, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			markFields(n.Recv)
+			mark(n.Type)
+		case *ast.FuncLit:
+			mark(n.Type)
+		case *ast.TypeSpec:
+			markFields(n.TypeParams)
+			mark(n.Type)
+		case *ast.ValueSpec:
+			mark(n.Type)
+		case *ast.CompositeLit:
+			mark(n.Type)
+		case *ast.TypeAssertExpr:
+			mark(n.Type)
+		case *ast.CallExpr:
+			switch fun := ast.Unparen(n.Fun).(type) {
+			case *ast.Ident:
+				if (fun.Name == "new" || fun.Name == "make") && len(n.Args) > 0 {
+					mark(n.Args[0])
+				}
+				mark(fun)
+			case *ast.SelectorExpr, *ast.ArrayType, *ast.MapType, *ast.ChanType, *ast.FuncType, *ast.StarExpr:
+				mark(fun)
+			case *ast.IndexExpr:
+				mark(fun.Index)
+			case *ast.IndexListExpr:
+				for _, index := range fun.Indices {
+					mark(index)
+				}
+			}
+		case *ast.TypeSwitchStmt:
+			for _, stmt := range n.Body.List {
+				for _, expr := range stmt.(*ast.CaseClause).List {
+					mark(expr)
+				}
+			}
+		}
+		return true
+	})
+	return positions
+}
+
+// typeIdents records in positions the offsets, relative to base, of the
+// identifiers naming types in the type expression expr.
+func typeIdents(expr ast.Expr, base int, positions map[int]bool) {
+	mark := func(expr ast.Expr) {
+		if expr != nil {
+			typeIdents(expr, base, positions)
+		}
+	}
+	markFields := func(fields *ast.FieldList) {
+		if fields != nil {
+			for _, field := range fields.List {
+				mark(field.Type)
+			}
+		}
+	}
+
+	switch t := expr.(type) {
+	case *ast.Ident:
+		positions[int(t.Pos())-base] = true
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok {
+			positions[int(x.Pos())-base] = true
+		}
+	case *ast.ParenExpr:
+		mark(t.X)
+	case *ast.StarExpr:
+		mark(t.X)
+	case *ast.UnaryExpr:
+		mark(t.X)
+	case *ast.BinaryExpr:
+		mark(t.X)
+		mark(t.Y)
+	case *ast.Ellipsis:
+		mark(t.Elt)
+	case *ast.ArrayType:
+		mark(t.Elt)
+	case *ast.MapType:
+		mark(t.Key)
+		mark(t.Value)
+	case *ast.ChanType:
+		mark(t.Value)
+	case *ast.IndexExpr:
+		mark(t.X)
+		mark(t.Index)
+	case *ast.IndexListExpr:
+		mark(t.X)
+		for _, index := range t.Indices {
+			mark(index)
+		}
+	case *ast.FuncType:
+		markFields(t.TypeParams)
+		markFields(t.Params)
+		markFields(t.Results)
+	case *ast.StructType:
+		markFields(t.Fields)
+	case *ast.InterfaceType:
+		markFields(t.Methods)
+	}
+}
+
+// qualifiedIdent matches an identifier qualified by the import path of its
+// package, as in types printed by the type checker, e.g. "io.Reader" or
+// "github.com/docaura/docaura-cli/pkg/analyzer.PackageInfo".
+var qualifiedIdent = regexp.MustCompile(`(\w[\w.~-]*(?:/[\w.~-]+)*)\.(\w+)`)
+
+// unqualify rewrites a type printed by the type checker the way the source
+// of the package writes it, naming packages instead of their import paths,
+// and returns it with its references to documented symbols. Since the import
+// paths are known, the references do not depend on the imports of the
+// package.
+func (r *docRenderer) unqualify(qualified string, anchor func(r *docRenderer, kind, recv, name string) string) (string, []reference) {
+	if r.pkg == nil {
+		return qualified, nil
+	}
+
+	var sb strings.Builder
+	var refs []reference
+	last := 0
+	for _, m := range qualifiedIdent.FindAllStringSubmatchIndex(qualified, -1) {
+		sb.WriteString(qualified[last:m[0]])
+		importPath, name := qualified[m[2]:m[3]], qualified[m[4]:m[5]]
+
+		start := sb.Len()
+		if qualifier := r.project().ImportName(r.pkg, importPath); qualifier != "." {
+			sb.WriteString(qualifier + ".")
+		}
+		sb.WriteString(name)
+		if url := r.importURL(importPath, name, anchor); url != "" {
+			refs = append(refs, reference{start: start, end: sb.Len(), url: url})
+		}
+		last = m[1]
+	}
+	sb.WriteString(qualified[last:])
+
+	// The local types are found in the rewritten type, which parses
+	refs = append(refs, findReferences(sb.String(), func(qualifier, name string) string {
+		if qualifier != "" {
+			return ""
+		}
+		return r.typeURL("", name, anchor)
+	})...)
+	slices.SortFunc(refs, func(a, b reference) int { return a.start - b.start })
+
+	return sb.String(), refs
+}
+
+// typeReferences returns the type to display and its references to
+// documented symbols. A type printed by the type checker, when given,
+// replaces the one written in the source.
+func (r *docRenderer) typeReferences(typ string, qualified []string, anchor func(r *docRenderer, kind, recv, name string) string) (string, []reference) {
+	if len(qualified) > 0 && qualified[0] != "" {
+		return r.unqualify(qualified[0], anchor)
+	}
+	return typ, findReferences(typ, func(qualifier, name string) string {
+		return r.typeURL(qualifier, name, anchor)
+	})
+}
+
+// typeURL returns the URL documenting an identifier found in the Go source
+// of the package, or an empty string when it is not documented. Unqualified
+// identifiers link to the types of the package, qualified ones to the
+// exported symbols of the project packages and, for other imports, to
+// pkg.go.dev.
+func (r *docRenderer) typeURL(qualifier, name string, anchor func(r *docRenderer, kind, recv, name string) string) string {
+	if r.pkg == nil {
+		return ""
+	}
+
+	if qualifier == "" {
+		if r.symbolKind("", name) != "type" {
+			return ""
+		}
+		return anchor(r, "type", "", name)
+	}
+
+	importPath := r.project().ResolveQualifier(r.pkg, qualifier)
+	if importPath == "" {
+		return ""
+	}
+	return r.importURL(importPath, name, anchor)
+}
+
+// importURL returns the URL documenting the exported symbol name of the
+// package with the given import path: its anchor on the page of a project
+// package, or its pkg.go.dev page for other packages.
+func (r *docRenderer) importURL(importPath, name string, anchor func(r *docRenderer, kind, recv, name string) string) string {
+	if !ast.IsExported(name) {
+		return ""
+	}
+	if target, page := r.pageOf(importPath); target != nil {
+		kind := target.symbolKind("", name)
+		if kind == "" {
+			return ""
+		}
+		return page + anchor(target, kind, "", name)
+	}
+	return docLinkBaseURL + "/" + importPath + "#" + name
+}
+
+// markdownType formats a Go type as inline code, linking the types it
+// refers to, e.g. "`[]*`[`analyzer.PackageInfo`](../analyzer/index.md#packageinfo)".
+// The type printed by the type checker, if passed as well, is preferred.
+// The result can be used in table cells.
+func (r *docRenderer) markdownType(typ string, qualified ...string) string {
+	typ, refs := r.typeReferences(typ, qualified, (*docRenderer).markdownAnchor)
+
+	var sb strings.Builder
+	last := 0
+	for _, ref := range refs {
+		sb.WriteString(codeCell(typ[last:ref.start]))
+		sb.WriteString("[" + codeCell(typ[ref.start:ref.end]) + "](" + ref.url + ")")
+		last = ref.end
+	}
+	sb.WriteString(codeCell(typ[last:]))
+	return sb.String()
+}
+
+// htmlType formats a Go type as HTML code, linking the types it refers to.
+// The type printed by the type checker, if passed as well, is preferred.
+func (r *docRenderer) htmlType(typ string, qualified ...string) htmltemplate.HTML {
+	typ, refs := r.typeReferences(typ, qualified, (*docRenderer).htmlAnchor)
+
+	var sb strings.Builder
+	sb.WriteString("<code>")
+	last := 0
+	for _, ref := range refs {
+		sb.WriteString(html.EscapeString(typ[last:ref.start]))
+		sb.WriteString(`<a href="` + html.EscapeString(ref.url) + `">`)
+		sb.WriteString(html.EscapeString(typ[ref.start:ref.end]))
+		sb.WriteString("</a>")
+		last = ref.end
+	}
+	sb.WriteString(html.EscapeString(typ[last:]))
+	sb.WriteString("</code>")
+	return htmltemplate.HTML(sb.String())
+}
+
+// highlight renders Go source like highlightGo, linking the identifiers
+// that refer to documented types.
+func (r *docRenderer) highlight(src string) htmltemplate.HTML {
+	return highlightLinked(src, findReferences(src, func(qualifier, name string) string {
+		return r.typeURL(qualifier, name, (*docRenderer).htmlAnchor)
+	}))
+}
@@ -0,0 +1,54 @@
+package docgen
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFindReferences(t *testing.T) {
+	// Kind and Shape are types; NewCircle is not
+	url := func(qualifier, name string) string {
+		if qualifier == "" && (name == "Kind" || name == "Shape") || qualifier == "io" {
+			return "#" + qualifier + name
+		}
+		return ""
+	}
+
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{name: "type", src: "map[string][]*Kind", want: []string{"Kind"}},
+		{name: "qualified type", src: "func(r io.Reader) Kind", want: []string{"io.Reader", "Kind"}},
+		{name: "declared type", src: "type Kind int"},
+		{name: "method named like a type", src: "func (c *Circle) Kind() Kind", want: []string{"Kind"}},
+		{
+			name: "interface methods",
+			src:  "type Shape interface {\n\t// Kind reports the kind.\n\tKind() Kind\n\tio.Reader\n}",
+			want: []string{"Kind", "io.Reader"},
+		},
+		{name: "field named like a type", src: "type T struct {\n\tKind Kind\n\tShape\n}", want: []string{"Kind", "Shape"}},
+		{name: "constant", src: "const KindRect Kind = iota + 1", want: []string{"Kind"}},
+		{name: "conversion", src: "Kind(3)", want: []string{"Kind"}},
+		{name: "selector", src: "s.Kind() == Kind(0)", want: []string{"Kind"}},
+		{
+			name: "statements",
+			src:  "s := NewCircle(2)\nvar k Kind = s.Kind()\nif _, ok := any(s).(Shape); ok {\n\tfmt.Println(k)\n}",
+			want: []string{"Kind", "Shape"},
+		},
+		{name: "invalid", src: "func ( Kind"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, ref := range findReferences(tt.src, url) {
+				got = append(got, tt.src[ref.start:ref.end])
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("findReferences(%q) = %q, want %q", tt.src, got, tt.want)
+			}
+		})
+	}
+}
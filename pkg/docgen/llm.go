@@ -0,0 +1,84 @@
+package docgen
+
+import (
+	"fmt"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/anthropic"
+	"github.com/tmc/langchaingo/llms/ollama"
+	"github.com/tmc/langchaingo/llms/openai"
+	"os"
+)
+
+// defaultLLMProvider is used when no provider is configured.
+const defaultLLMProvider = "groq"
+
+// llmPreset holds the default connection settings for a supported provider.
+type llmPreset struct {
+	baseURL   string
+	model     string
+	apiKeyEnv string
+}
+
+// llmPresets maps provider names to their default settings.
+var llmPresets = map[string]llmPreset{
+	"groq": {
+		baseURL:   "https://api.groq.com/openai/v1",
+		model:     "llama3-8b-8192",
+		apiKeyEnv: "GROQ_API_KEY",
+	},
+	"openai": {
+		model:     "gpt-4o-mini",
+		apiKeyEnv: "OPENAI_API_KEY",
+	},
+	"ollama": {
+		model: "llama3",
+	},
+	"anthropic": {
+		model:     "claude-3-haiku-20240307",
+		apiKeyEnv: "ANTHROPIC_API_KEY",
+	},
+	"fake": {
+		model: "fake",
+	},
+}
+
+// newLLM creates an LLM client for the configured provider.
+func newLLM(config LLMConfig) (llms.Model, error) {
+	var token string
+	if config.APIKeyEnv != "" {
+		token = os.Getenv(config.APIKeyEnv)
+	}
+
+	switch config.Provider {
+	case "groq", "openai":
+		opts := []openai.Option{
+			openai.WithModel(config.Model),
+			openai.WithToken(token),
+		}
+		if config.BaseURL != "" {
+			opts = append(opts, openai.WithBaseURL(config.BaseURL))
+		}
+		return openai.New(opts...)
+	case "ollama":
+		opts := []ollama.Option{
+			ollama.WithModel(config.Model),
+		}
+		if config.BaseURL != "" {
+			opts = append(opts, ollama.WithServerURL(config.BaseURL))
+		}
+		return ollama.New(opts...)
+	case "anthropic":
+		opts := []anthropic.Option{
+			anthropic.WithModel(config.Model),
+			anthropic.WithToken(token),
+		}
+		if config.BaseURL != "" {
+			opts = append(opts, anthropic.WithBaseURL(config.BaseURL))
+		}
+		return anthropic.New(opts...)
+	case "fake":
+		return NewFakeLLM(), nil
+	default:
+		return nil, fmt.Errorf("unsupported LLM provider %q", config.Provider)
+	}
+}
@@ -0,0 +1,78 @@
+package docgen
+
+import "errors"
+
+// OutcomeStatus describes what happened to a single enhancement task.
+type OutcomeStatus string
+
+const (
+	// OutcomeEnhanced means the LLM produced new content.
+	OutcomeEnhanced OutcomeStatus = "enhanced"
+	// OutcomeCached means content was reused from the cache.
+	OutcomeCached OutcomeStatus = "cached"
+	// OutcomeSkipped means no LLM call was needed.
+	OutcomeSkipped OutcomeStatus = "skipped"
+	// OutcomeFailed means the LLM call failed; Reason holds the error.
+	OutcomeFailed OutcomeStatus = "failed"
+)
+
+// Enhancement task names.
+const (
+	TaskDescription = "description"
+	TaskExample     = "example"
+)
+
+// Outcome records the result of enhancing one symbol.
+type Outcome struct {
+	Symbol string        `json:"symbol"`
+	Task   string        `json:"task"`
+	Status OutcomeStatus `json:"status"`
+	Reason string        `json:"reason,omitempty"`
+}
+
+// Result holds the generated documentation for a package together with the
+// outcome of every enhancement task.
+type Result struct {
+	Content  string    `json:"content"`
+	Outcomes []Outcome `json:"outcomes"`
+}
+
+// Failed returns the outcomes whose enhancement failed.
+func (r *Result) Failed() []Outcome {
+	var failed []Outcome
+	for _, o := range r.Outcomes {
+		if o.Status == OutcomeFailed {
+			failed = append(failed, o)
+		}
+	}
+	return failed
+}
+
+// errEmptyResponse is reported when the LLM returns no usable content.
+var errEmptyResponse = errors.New("empty response")
+
+// newOutcome builds the outcome of an LLM task from its result.
+func newOutcome(symbol, task, content string, cached bool, err error) Outcome {
+	outcome := Outcome{Symbol: symbol, Task: task}
+
+	if err == nil && content == "" {
+		err = errEmptyResponse
+	}
+
+	switch {
+	case err != nil:
+		outcome.Status = OutcomeFailed
+		outcome.Reason = err.Error()
+	case cached:
+		outcome.Status = OutcomeCached
+	default:
+		outcome.Status = OutcomeEnhanced
+	}
+
+	return outcome
+}
+
+// skippedOutcome builds the outcome of a task that needed no LLM call.
+func skippedOutcome(symbol, task, reason string) Outcome {
+	return Outcome{Symbol: symbol, Task: task, Status: OutcomeSkipped, Reason: reason}
+}
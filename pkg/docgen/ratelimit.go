@@ -0,0 +1,160 @@
+package docgen
+
+import (
+	"context"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults for the enhancement pipeline.
+const (
+	defaultConcurrency = 4
+	defaultMaxRetries  = 3
+	baseRetryDelay     = time.Second
+	maxRetryDelay      = 30 * time.Second
+)
+
+// rateLimiter limits LLM usage to a number of requests and tokens per minute.
+// A zero limit disables the corresponding check.
+type rateLimiter struct {
+	mu       sync.Mutex
+	requests *bucket
+	tokens   *bucket
+}
+
+// bucket is a token bucket that refills continuously over one minute.
+type bucket struct {
+	capacity  float64
+	available float64
+	last      time.Time
+}
+
+// newRateLimiter creates a limiter for the given per-minute limits.
+func newRateLimiter(requestsPerMinute, tokensPerMinute int) *rateLimiter {
+	return &rateLimiter{
+		requests: newBucket(requestsPerMinute),
+		tokens:   newBucket(tokensPerMinute),
+	}
+}
+
+// newBucket creates a full bucket, or nil when perMinute is not positive.
+func newBucket(perMinute int) *bucket {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &bucket{
+		capacity:  float64(perMinute),
+		available: float64(perMinute),
+		last:      time.Now(),
+	}
+}
+
+// refill adds the capacity accrued since the last refill.
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.last)
+	b.last = now
+	b.available += elapsed.Minutes() * b.capacity
+	if b.available > b.capacity {
+		b.available = b.capacity
+	}
+}
+
+// delay returns how long to wait until n units are available.
+func (b *bucket) delay(n float64) time.Duration {
+	if b.available >= n {
+		return 0
+	}
+	missing := n - b.available
+	return time.Duration(missing / b.capacity * float64(time.Minute))
+}
+
+// wait blocks until one request and the given number of tokens may be spent,
+// or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context, tokens int) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		var delay time.Duration
+
+		if l.requests != nil {
+			l.requests.refill(now)
+			delay = max(delay, l.requests.delay(1))
+		}
+
+		need := float64(tokens)
+		if l.tokens != nil {
+			// A single request larger than the budget can only wait for a full bucket
+			need = min(need, l.tokens.capacity)
+			l.tokens.refill(now)
+			delay = max(delay, l.tokens.delay(need))
+		}
+
+		if delay == 0 {
+			if l.requests != nil {
+				l.requests.available--
+			}
+			if l.tokens != nil {
+				l.tokens.available -= need
+			}
+			l.mu.Unlock()
+			return nil
+		}
+		l.mu.Unlock()
+
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// estimateTokens approximates the number of tokens in a prompt.
+func estimateTokens(text string) int {
+	return len(text)/4 + 1
+}
+
+// statusCodePattern matches the HTTP status code reported by provider errors.
+var statusCodePattern = regexp.MustCompile(`(?:status code:?\s*|^)(\d{3})\b`)
+
+// isRetryable reports whether an LLM error is worth retrying: rate limiting
+// (429) and server-side (5xx) failures.
+func isRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	msg := err.Error()
+	if match := statusCodePattern.FindStringSubmatch(msg); match != nil {
+		code, _ := strconv.Atoi(match[1])
+		return code == 429 || code >= 500
+	}
+
+	return strings.Contains(strings.ToLower(msg), "rate limit")
+}
+
+// retryDelay returns the exponential backoff delay for a retry attempt,
+// with jitter to spread out concurrent retries.
+func retryDelay(attempt int) time.Duration {
+	delay := baseRetryDelay << attempt
+	if delay <= 0 || delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay) / 2))
+	return delay/2 + jitter
+}
+
+// sleepContext sleeps for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,147 @@
+package docgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "too many requests", err: errors.New("API returned unexpected status code: 429: slow down"), want: true},
+		{name: "server error", err: errors.New("status code 500"), want: true},
+		{name: "overloaded", err: errors.New("529 overloaded_error"), want: true},
+		{name: "wrapped", err: fmt.Errorf("generate: %w", errors.New("status code: 503")), want: true},
+		{name: "bad request", err: errors.New("status code: 400: invalid model"), want: false},
+		{name: "unauthorized", err: errors.New("401 Unauthorized"), want: false},
+		{name: "rate limit message", err: errors.New("Rate limit exceeded, retry later"), want: true},
+		{name: "number inside message", err: errors.New("context length 4096 exceeded"), want: false},
+		{name: "other", err: errors.New("connection refused"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.err); got != tt.want {
+				t.Errorf("isRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempt int
+		full    time.Duration // delay before jitter
+	}{
+		{attempt: 0, full: time.Second},
+		{attempt: 1, full: 2 * time.Second},
+		{attempt: 4, full: 16 * time.Second},
+		{attempt: 5, full: maxRetryDelay},
+		{attempt: 100, full: maxRetryDelay},
+	}
+
+	for _, tt := range tests {
+		for range 20 {
+			got := retryDelay(tt.attempt)
+			if got < tt.full/2 || got >= tt.full {
+				t.Fatalf("retryDelay(%d) = %v, want in [%v, %v)", tt.attempt, got, tt.full/2, tt.full)
+			}
+		}
+	}
+}
+
+func TestBucket(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := &bucket{capacity: 60, available: 60, last: start}
+
+	if d := b.delay(60); d != 0 {
+		t.Errorf("delay of a full bucket = %v, want 0", d)
+	}
+
+	b.available = 0
+	if d := b.delay(1); d != time.Second {
+		t.Errorf("delay(1) of an empty bucket = %v, want 1s", d)
+	}
+	if d := b.delay(30); d != 30*time.Second {
+		t.Errorf("delay(30) of an empty bucket = %v, want 30s", d)
+	}
+
+	b.refill(start.Add(10 * time.Second))
+	if b.available != 10 {
+		t.Errorf("available after 10s = %v, want 10", b.available)
+	}
+	if d := b.delay(15); d != 5*time.Second {
+		t.Errorf("delay(15) after 10s = %v, want 5s", d)
+	}
+
+	// Refilling never exceeds the capacity
+	b.refill(start.Add(time.Hour))
+	if b.available != 60 {
+		t.Errorf("available after an hour = %v, want 60", b.available)
+	}
+}
+
+func TestNewBucketDisabled(t *testing.T) {
+	for _, perMinute := range []int{0, -1} {
+		if b := newBucket(perMinute); b != nil {
+			t.Errorf("newBucket(%d) = %+v, want nil", perMinute, b)
+		}
+	}
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	l := newRateLimiter(2, 100)
+
+	ctx := context.Background()
+	if err := l.wait(ctx, 40); err != nil {
+		t.Fatalf("first wait: %v", err)
+	}
+	if err := l.wait(ctx, 10); err != nil {
+		t.Fatalf("second wait: %v", err)
+	}
+	if got := l.requests.available; got > 0.01 {
+		t.Errorf("requests available = %v, want 0", got)
+	}
+	if got := l.tokens.available; got < 50 || got > 50.1 {
+		t.Errorf("tokens available = %v, want 50", got)
+	}
+
+	// The request budget is spent, so waiting blocks until ctx is done
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := l.wait(cancelled, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("wait with an exhausted budget = %v, want context.Canceled", err)
+	}
+}
+
+func TestRateLimiterOversizedRequest(t *testing.T) {
+	l := newRateLimiter(0, 100)
+
+	// A request larger than the token budget waits for a full bucket instead
+	// of forever, then empties it
+	if err := l.wait(context.Background(), 1000); err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+	if got := l.tokens.available; got > 0.1 {
+		t.Errorf("tokens available = %v, want 0", got)
+	}
+}
+
+func TestRateLimiterUnlimited(t *testing.T) {
+	l := newRateLimiter(0, 0)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	for range 100 {
+		if err := l.wait(cancelled, 1_000_000); err != nil {
+			t.Fatalf("wait without limits: %v", err)
+		}
+	}
+}
@@ -2,19 +2,46 @@ package docgen
 
 import (
 	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"text/template"
 )
 
-// TemplateManager manages documentation templates.
+// TemplateManager manages documentation templates. Each style is a set of
+// named sections such as "package.md.tmpl", "func.md.tmpl" and "type.md.tmpl"
+// that can be overridden from a template directory.
 type TemplateManager struct {
-	templates map[string]*template.Template
+	templates map[string]executor
 }
 
+// executor is implemented by both text/template and html/template templates.
+type executor interface {
+	ExecuteTemplate(wr io.Writer, name string, data any) error
+}
+
+// styleExtensions maps each output style to the extension used in its
+// section names, e.g. "func.md.tmpl" for markdown.
+var styleExtensions = map[string]string{
+	"markdown": "md",
+	"godoc":    "txt",
+	"html":     "html",
+}
+
+// Template sections rendered directly by the generator.
+const (
+	sectionPackage = "package"
+	sectionIndex   = "index"
+)
+
 // NewTemplateManager creates a new template manager with default templates.
 func NewTemplateManager() (*TemplateManager, error) {
 	tm := &TemplateManager{
-		templates: make(map[string]*template.Template),
+		templates: make(map[string]executor),
 	}
 
 	if err := tm.loadDefaultTemplates(); err != nil {
@@ -24,138 +51,426 @@ func NewTemplateManager() (*TemplateManager, error) {
 	return tm, nil
 }
 
-// Execute executes the template for the given style.
-func (tm *TemplateManager) Execute(style string, data interface{}) (string, error) {
+// Execute executes the package template for the given style. Doc comments
+// and type references are linked to the symbols of the page's package and,
+// when the page is part of a site, to the pages of other project packages.
+func (tm *TemplateManager) Execute(style string, data *PageData) (string, error) {
+	return tm.execute(style, sectionPackage, data, newDocRenderer(data).funcs(style))
+}
+
+// ExecuteSection executes a named section of the templates for the given style.
+func (tm *TemplateManager) ExecuteSection(style, section string, data interface{}) (string, error) {
+	return tm.execute(style, section, data, nil)
+}
+
+// execute executes a section on a copy of the style's templates with funcs
+// overriding the built-in functions. The built-in templates themselves are
+// never executed, since html/template cannot clone templates afterwards.
+func (tm *TemplateManager) execute(style, section string, data any, funcs map[string]any) (string, error) {
 	tmpl, exists := tm.templates[style]
 	if !exists {
 		return "", fmt.Errorf("template for style %q not found", style)
 	}
 
+	var clone executor
+	switch t := tmpl.(type) {
+	case *template.Template:
+		c, err := t.Clone()
+		if err != nil {
+			return "", fmt.Errorf("clone %s templates: %w", style, err)
+		}
+		clone = c.Funcs(funcs)
+	case *htmltemplate.Template:
+		c, err := t.Clone()
+		if err != nil {
+			return "", fmt.Errorf("clone %s templates: %w", style, err)
+		}
+		clone = c.Funcs(funcs)
+	default:
+		return "", fmt.Errorf("no built-in templates for style %q", style)
+	}
+
 	var result strings.Builder
-	if err := tmpl.Execute(&result, data); err != nil {
+	if err := clone.ExecuteTemplate(&result, sectionName(section, style), data); err != nil {
 		return "", fmt.Errorf("execute template: %w", err)
 	}
 
 	return result.String(), nil
 }
 
+// LoadDir overrides the built-in templates with the section files found in
+// dir. Files are named "<section>.<ext>.tmpl", where ext is md, html or txt
+// for the markdown, html and godoc styles; any section not present in dir
+// keeps its built-in definition.
+func (tm *TemplateManager) LoadDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("read template directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("template path %q is not a directory", dir)
+	}
+
+	for _, style := range slices.Sorted(maps.Keys(styleExtensions)) {
+		pattern := filepath.Join(dir, "*."+styleExtensions[style]+".tmpl")
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return fmt.Errorf("match templates %q: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			continue
+		}
+
+		var overridden executor
+		switch tmpl := tm.templates[style].(type) {
+		case *template.Template:
+			clone, err := tmpl.Clone()
+			if err != nil {
+				return fmt.Errorf("clone %s templates: %w", style, err)
+			}
+			overridden, err = clone.ParseGlob(pattern)
+			if err != nil {
+				return fmt.Errorf("parse %s templates: %w", style, err)
+			}
+		case *htmltemplate.Template:
+			clone, err := tmpl.Clone()
+			if err != nil {
+				return fmt.Errorf("clone %s templates: %w", style, err)
+			}
+			overridden, err = clone.ParseGlob(pattern)
+			if err != nil {
+				return fmt.Errorf("parse %s templates: %w", style, err)
+			}
+		default:
+			return fmt.Errorf("no built-in templates for style %q", style)
+		}
+
+		tm.templates[style] = overridden
+	}
+
+	return nil
+}
+
 // loadDefaultTemplates loads the default templates.
 func (tm *TemplateManager) loadDefaultTemplates() error {
 	// Markdown template
 	markdownTemplate := `# {{.Name}}
+{{- with .Description}}
 
-{{.Description}}
+{{doc .}}
+{{- end}}
+{{- with .ImportPath}}
 
 ## Installation
 
 ` + "```bash" + `
-go get {{.Path}}
+{{if eq $.Name "main"}}go install {{.}}@{{or $.Module.Version "latest"}}{{else}}go get {{.}}{{with $.Module.Version}}@{{.}}{{end}}{{end}}
 ` + "```" + `
+{{- if ne $.Name "main"}}
 
-## Usage
-
-{{if .Examples}}
-{{range .Examples}}
 ` + "```go" + `
-{{.Code}}
+import "{{.}}"
 ` + "```" + `
-{{end}}
-{{end}}
+{{- end}}
+{{- with $.Module.GoVersion}}
+
+Requires Go {{.}} or later.
+{{- end}}
+{{- end}}
+
+## Usage
+{{- range .Examples}}{{template "example.md.tmpl" .}}{{end}}
+{{- with .Notes}}
+
+## Known Issues
+{{range .}}
+- **{{.Marker}}**{{if .UID}}({{.UID}}){{end}}: {{.Body}}
+{{- end}}
+{{- end}}
 
 ## API Reference
+{{- with exportedDecls .ConstDecls ""}}
+
+### Constants
+{{- range .}}{{template "decl.md.tmpl" .}}{{end}}
+{{- end}}
+{{- with exportedDecls .VarDecls ""}}
+
+### Variables
+{{- range .}}{{template "decl.md.tmpl" .}}{{end}}
+{{- end}}
+{{- with plainFuncs .Functions}}
 
-{{if .Functions}}
 ### Functions
+{{- range .}}{{template "func.md.tmpl" .}}{{end}}
+{{- end}}
+{{- if .Types}}
 
-{{range .Functions}}
-{{if .IsExported}}
-#### {{.Name}}
+### Types
+{{- range .Types}}{{if .IsExported}}
+{{- template "type.md.tmpl" .}}
+{{- range exportedDecls $.ConstDecls .Name}}{{template "decl.md.tmpl" .}}{{end}}
+{{- range exportedDecls $.VarDecls .Name}}{{template "decl.md.tmpl" .}}{{end}}
+{{- range funcsOf $.Functions .Name}}{{template "func.md.tmpl" .}}{{end}}
+{{- range methodsOf $.Functions .Name}}{{template "func.md.tmpl" .}}{{end}}
+{{- end}}{{end}}
+{{- end}}
+{{- if .Private}}
+
+## Unexported Symbols
+
+These symbols are not part of the package's public API.
+{{- with unexportedDecls .ConstDecls}}
+
+### Unexported Constants
+{{- range .}}{{template "decl.md.tmpl" .}}{{end}}
+{{- end}}
+{{- with unexportedDecls .VarDecls}}
+
+### Unexported Variables
+{{- range .}}{{template "decl.md.tmpl" .}}{{end}}
+{{- end}}
+{{- with unexportedFuncs .Functions}}
+
+### Unexported Functions
+{{- range .}}{{template "func.md.tmpl" .}}{{end}}
+{{- end}}
+{{- with unexportedTypes .Types}}
+
+### Unexported Types
+{{- range .}}
+{{- template "type.md.tmpl" .}}
+{{- range exportedDecls $.ConstDecls .Name}}{{template "decl.md.tmpl" .}}{{end}}
+{{- range exportedDecls $.VarDecls .Name}}{{template "decl.md.tmpl" .}}{{end}}
+{{- range funcsOf $.Functions .Name}}{{template "func.md.tmpl" .}}{{end}}
+{{- range methodsOf $.Functions .Name}}{{template "func.md.tmpl" .}}{{end}}
+{{- end}}
+{{- end}}
+{{- with unexportedMethods .Functions}}
+
+### Unexported Methods
+{{- range .}}{{template "func.md.tmpl" .}}{{end}}
+{{- end}}
+{{- end}}
+{{define "func.md.tmpl"}}
+
+{{if or .IsMethod .AssociatedType}}#####{{else}}####{{end}} {{if .IsMethod}}{{.Receiver}}.{{end}}{{.Name}}
+{{- template "deprecated.md.tmpl" .}}
+{{- template "build.md.tmpl" .}}
 
 ` + "```go" + `
 {{.Signature}}
 ` + "```" + `
+{{- with .Description}}
+
+{{doc .}}
+{{- end}}
+{{- if .TypeParams}}
 
-{{.Description}}
+**Type Parameters:**
+{{range .TypeParams}}
+- ` + "`{{.Name}}`" + ` {{linkType .Constraint}}
+{{- end}}
+{{- end}}
+{{- if .Parameters}}
 
-{{if .Parameters}}
 **Parameters:**
 {{range .Parameters}}
-- ` + "`{{.Name}}`" + ` ({{.Type}})
-{{end}}
-{{end}}
+- ` + "`{{.Name}}`" + ` ({{linkType .Type .QualifiedType}})
+{{- end}}
+{{- end}}
+{{- if .Returns}}
 
-{{if .Returns}}
 **Returns:**
 {{range .Returns}}
-- {{.Type}}{{if .Description}} - {{.Description}}{{end}}
-{{end}}
-{{end}}
+- {{if .Name}}` + "`{{.Name}}`" + ` {{end}}{{linkType .Type .QualifiedType}}{{if .Description}} - {{.Description}}{{end}}
+{{- end}}
+{{- end}}
+{{- with separateExamples .Examples}}
 
-{{if .Examples}}
 **Example:**
-{{range .Examples}}
+{{- range .}}{{template "example.md.tmpl" .}}{{end}}
+{{- end}}
+{{- end}}
+{{- define "type.md.tmpl"}}
+
+#### {{.Name}}
+{{- template "deprecated.md.tmpl" .}}
+{{- template "build.md.tmpl" .}}
+
 ` + "```go" + `
-{{.}}
+{{if .Decl}}{{trimSpace .Decl}}{{else}}type {{.Name}}{{typeParams .TypeParams}} {{.Kind}}{{constraintBody .Constraint}}{{end}}
 ` + "```" + `
-{{end}}
-{{end}}
+{{- with .Description}}
 
-{{end}}
-{{end}}
-{{end}}
+{{doc .}}
+{{- end}}
+{{- if .TypeParams}}
 
-{{if .Types}}
-### Types
+**Type Parameters:**
+{{range .TypeParams}}
+- ` + "`{{.Name}}`" + ` {{linkType .Constraint}}
+{{- end}}
+{{- end}}
+{{- if .Fields}}
 
-{{range .Types}}
-{{if .IsExported}}
-#### {{.Name}}
+**Fields:**
+
+| Field | Type | Tags | Description |
+| --- | --- | --- | --- |
+{{- range .Fields}}
+| ` + "`{{.Name}}`" + `{{if .Embedded}} *(embedded)*{{end}} | {{linkType .Type .QualifiedType}} | {{range $key, $value := .Tags}}{{codeCell (printf "%s:%q" $key $value)}} {{end}}| {{tableCell .Description}}{{if .Promoted}}{{if .Description}} {{end}}Promotes {{range $i, $m := .Promoted}}{{if $i}}, {{end}}` + "`{{$m}}`" + `{{end}}.{{end}} |
+{{- end}}
+{{- end}}
+{{- with .Enum}}
+
+**Values:**{{if .HasString}} (printed by name via ` + "`String()`" + `){{end}}
+
+| Constant | Value | Description |
+| --- | --- | --- |
+{{- range .Members}}
+| ` + "`{{.Name}}`" + ` | {{codeCell .Value}} | {{tableCell .Description}} |
+{{- end}}
+{{- end}}
+{{- if .Embeds}}
+
+**Embeds:** {{range $i, $embed := .Embeds}}{{if $i}}, {{end}}{{linkType $embed}}{{end}}
+{{- end}}
+{{- if .InterfaceMethods}}
+
+**Interface Methods:**
+{{range .InterfaceMethods}}
+- ` + "`{{.Signature}}`" + `{{if .Description}} - {{.Description}}{{end}}
+{{- end}}
+{{- end}}
+{{- with separateExamples .Examples}}
+
+**Example:**
+{{- range .}}{{template "example.md.tmpl" .}}{{end}}
+{{- end}}
+{{- if .Implements}}
+
+**Implements:** {{range $i, $iface := .Implements}}{{if $i}}, {{end}}{{linkType $iface}}{{end}}
+{{- end}}
+{{- if .PromotedMethods}}
+
+**Promoted Methods:**
+{{range .PromotedMethods}}
+- ` + "`{{.Name}}{{trimPrefix \"func\" .Signature}}`" + ` (from ` + "`{{.From}}`" + `)
+{{- end}}
+{{- end}}
+{{- end}}
+{{- define "deprecated.md.tmpl"}}
+{{- if .Deprecated}}
+
+{{blockquote (printf "**Deprecated:** %s" (or (doc .DeprecationMessage) "This symbol should no longer be used."))}}
+{{- end}}
+{{- end}}
+{{- define "build.md.tmpl"}}
+{{- if .BuildConstraint}}
+
+> **Build:** {{buildNote .BuildConstraint}}
+{{- end}}
+{{- end}}
+{{- define "decl.md.tmpl"}}
+{{- template "deprecated.md.tmpl" .}}
+{{- template "build.md.tmpl" .}}
 
 ` + "```go" + `
-type {{.Name}} {{.Kind}}
+{{trimSpace .Source}}
 ` + "```" + `
+{{- with .Description}}
 
-{{.Description}}
+{{doc .}}
+{{- end}}
+{{- end}}
+{{- define "example.md.tmpl"}}
+{{- if .Doc}}
 
-{{if .Fields}}
-**Fields:**
-{{range .Fields}}
-- ` + "`{{.Name}}`" + ` {{.Type}}{{if .Description}} - {{.Description}}{{end}}
-{{end}}
-{{end}}
-
-{{if .Methods}}
-**Methods:**
-{{range .Methods}}
-- [{{.}}](#{{. | lower}})
-{{end}}
-{{end}}
-
-{{end}}
-{{end}}
-{{end}}
-`
-
-	if err := tm.addTemplate("markdown", markdownTemplate); err != nil {
+{{doc .Doc}}
+{{- end}}
+
+` + "```go" + `
+{{.Code}}
+` + "```" + `
+{{- if .Output}}
+
+Output:
+
+` + "```" + `
+{{trimSpace .Output}}
+` + "```" + `
+{{- end}}
+{{- end}}`
+
+	if err := tm.addTemplate("markdown", markdownTemplate, markdownIndexTemplate); err != nil {
 		return fmt.Errorf("add markdown template: %w", err)
 	}
 
-	// Add other templates (godoc, html) here as needed
+	if err := tm.addTemplate("godoc", godocTemplate, godocIndexTemplate); err != nil {
+		return fmt.Errorf("add godoc template: %w", err)
+	}
+
+	if err := tm.addHTMLTemplate("html", htmlPackageTemplate, htmlIndexTemplate); err != nil {
+		return fmt.Errorf("add html template: %w", err)
+	}
 
 	return nil
 }
 
-// addTemplate adds a template with the given name and content.
-func (tm *TemplateManager) addTemplate(name, content string) error {
-	funcMap := template.FuncMap{
-		"lower": strings.ToLower,
+// markdownIndexTemplate renders the index page linking every package.
+const markdownIndexTemplate = `{{define "index.md.tmpl"}}# {{if .ProjectName}}{{.ProjectName}}{{else}}Packages{{end}}
+{{- if .ProjectDesc}}
+
+{{.ProjectDesc}}
+{{- end}}
+
+## Packages
+
+| Package | Path | Description |
+| --- | --- | --- |
+{{- range .Packages}}
+| [{{.Name}}]({{.Link}}) | ` + "`{{.Path}}`" + ` | {{escapeMarkdown (firstSentence .Description)}} |
+{{- end}}
+{{- with .Graph}}
+
+## Dependencies
+
+` + "```mermaid" + `
+{{mermaid .}}` + "```" + `
+{{- end}}
+{{end}}`
+
+// addTemplate adds the templates for a style. The first content defines the
+// package section; further contents are parsed into the same set.
+func (tm *TemplateManager) addTemplate(style string, contents ...string) error {
+	funcMap := template.FuncMap(baseFuncMap())
+	maps.Copy(funcMap, newDocRenderer(nil).funcs(style))
+	tmpl := template.New(sectionName(sectionPackage, style)).Funcs(funcMap)
+	for _, content := range contents {
+		if _, err := tmpl.Parse(content); err != nil {
+			return fmt.Errorf("parse template %q: %w", style, err)
+		}
 	}
 
-	tmpl, err := template.New(name).Funcs(funcMap).Parse(content)
-	if err != nil {
-		return fmt.Errorf("parse template %q: %w", name, err)
+	tm.templates[style] = tmpl
+	return nil
+}
+
+// addHTMLTemplate adds the HTML templates for a style with contextual escaping.
+func (tm *TemplateManager) addHTMLTemplate(style string, contents ...string) error {
+	tmpl := htmltemplate.New(sectionName(sectionPackage, style)).Funcs(htmlFuncMap())
+	for _, content := range contents {
+		if _, err := tmpl.Parse(content); err != nil {
+			return fmt.Errorf("parse template %q: %w", style, err)
+		}
 	}
 
-	tm.templates[name] = tmpl
+	tm.templates[style] = tmpl
 	return nil
 }
+
+// sectionName returns the template name of a section for a style.
+func sectionName(section, style string) string {
+	return section + "." + styleExtensions[style] + ".tmpl"
+}
@@ -0,0 +1,12 @@
+package shapes_test
+
+import (
+	"fmt"
+	"github.com/docaura/docaura-cli/pkg/docgen/testdata/shapes"
+)
+
+func ExampleNewCircle() {
+	c := shapes.NewCircle(2)
+	fmt.Printf("%.2f\n", c.Area())
+	// Output: 12.57
+}
@@ -0,0 +1,95 @@
+// Package shapes computes the geometry of simple shapes.
+package shapes
+
+import (
+	"fmt"
+	"math"
+)
+
+// Kind identifies a kind of shape.
+type Kind int
+
+// Kinds of shapes.
+const (
+	KindCircle Kind = iota // a round shape
+	KindRect               // a four-sided shape
+)
+
+// String names the kind.
+func (k Kind) String() string {
+	switch k {
+	case KindCircle:
+		return "circle"
+	case KindRect:
+		return "rect"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Shape is implemented by every shape.
+type Shape interface {
+	// Area returns the area of the shape.
+	Area() float64
+	// Kind reports the kind of the shape.
+	Kind() Kind
+}
+
+// Circle is a circle centered on the origin.
+type Circle struct {
+	// Radius is the distance from the center to the edge.
+	Radius float64 `json:"radius"`
+	Label  string  `json:"label,omitempty"` // shown next to the shape
+}
+
+// NewCircle returns a circle of radius r.
+func NewCircle(r float64) *Circle {
+	return &Circle{Radius: r}
+}
+
+// Area returns the area of the circle.
+func (c *Circle) Area() float64 {
+	return math.Pi * c.Radius * c.Radius
+}
+
+// Kind reports KindCircle.
+func (c *Circle) Kind() Kind {
+	return KindCircle
+}
+
+// Largest returns the shape with the largest area, or nil if there are no
+// shapes.
+func Largest[S Shape](shapes ...S) (largest S, ok bool) {
+	for i, s := range shapes {
+		if i == 0 || s.Area() > largest.Area() {
+			largest, ok = s, true
+		}
+	}
+	return largest, ok
+}
+
+// TotalArea sums the areas of the shapes.
+//
+// Deprecated: Sum the areas with a loop over [Shape.Area] instead.
+func TotalArea(shapes []Shape) float64 {
+	var total float64
+	for _, s := range shapes {
+		total += s.Area()
+	}
+	return total
+}
+
+// defaultRadius is the radius of a circle made without one.
+const defaultRadius = 1.0
+
+// diameter returns twice the radius.
+func (c *Circle) diameter() float64 {
+	return 2 * c.Radius
+}
+
+// unitSquare is a square with sides of length one.
+type unitSquare struct{}
+
+// scaleArea multiplies the area of s by f.
+func scaleArea(s Shape, f float64) float64 {
+	return s.Area() * f
+}